@@ -1,7 +1,9 @@
 package atom
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/mmcdole/gofeed/extensions"
@@ -9,23 +11,28 @@ import (
 
 // Feed is an Atom Feed
 type Feed struct {
-	Title         string         `json:"title,omitempty"`
-	ID            string         `json:"id,omitempty"`
-	Updated       string         `json:"updated,omitempty"`
-	UpdatedParsed *time.Time     `json:"updatedParsed,omitempty"`
-	Subtitle      string         `json:"subtitle,omitempty"`
-	Links         []*Link        `json:"links,omitempty"`
-	Language      string         `json:"language,omitempty"`
-	Generator     *Generator     `json:"generator,omitempty"`
-	Icon          string         `json:"icon,omitempty"`
-	Logo          string         `json:"logo,omitempty"`
-	Rights        string         `json:"rights,omitempty"`
-	Contributors  []*Person      `json:"contributors,omitempty"`
-	Authors       []*Person      `json:"authors,omitempty"`
-	Categories    []*Category    `json:"categories,omitempty"`
-	Entries       []*Entry       `json:"entries"`
-	Extensions    ext.Extensions `json:"extensions,omitempty"`
-	Version       string         `json:"version"`
+	Title           string            `json:"title,omitempty"`
+	ID              string            `json:"id,omitempty"`
+	Updated         string            `json:"updated,omitempty"`
+	UpdatedParsed   *time.Time        `json:"updatedParsed,omitempty"`
+	Published       string            `json:"published,omitempty"`
+	PublishedParsed *time.Time        `json:"publishedParsed,omitempty"`
+	Subtitle        string            `json:"subtitle,omitempty"`
+	Links           []*Link           `json:"links,omitempty"`
+	Language        string            `json:"language,omitempty"`
+	Generator       *Generator        `json:"generator,omitempty"`
+	Icon            string            `json:"icon,omitempty"`
+	Logo            string            `json:"logo,omitempty"`
+	Rights          string            `json:"rights,omitempty"`
+	Contributors    []*Person         `json:"contributors,omitempty"`
+	Authors         []*Person         `json:"authors,omitempty"`
+	Categories      []*Category       `json:"categories,omitempty"`
+	EntryCount      int               `json:"entryCount,omitempty"` // Only set with Parser.MetadataOnly
+	Entries         []*Entry          `json:"entries"`
+	Extensions      ext.Extensions    `json:"extensions,omitempty"`
+	Namespaces      map[string]string `json:"namespaces,omitempty"` // Declared on the root element, url to prefix
+	Version         string            `json:"version"`
+	Warnings        []ParseWarning    `json:"warnings,omitempty"`
 }
 
 func (f Feed) String() string {
@@ -40,6 +47,8 @@ type Entry struct {
 	Updated         string         `json:"updated,omitempty"`
 	UpdatedParsed   *time.Time     `json:"updatedParsed,omitempty"`
 	Summary         string         `json:"summary,omitempty"`
+	SummaryType     string         `json:"summaryType,omitempty"`
+	Language        string         `json:"language,omitempty"`
 	Authors         []*Person      `json:"authors,omitempty"`
 	Contributors    []*Person      `json:"contributors,omitempty"`
 	Categories      []*Category    `json:"categories,omitempty"`
@@ -76,14 +85,22 @@ type Link struct {
 	Type     string `json:"type,omitempty"`
 	Title    string `json:"title,omitempty"`
 	Length   string `json:"length,omitempty"`
+	Count    string `json:"count,omitempty"` // thr:count of a replies link
 }
 
 // Content either contains or links to the content of
-// the entry
+// the entry. Content with a binary media type keeps the
+// undecoded payload in Base64.
 type Content struct {
-	Src   string `json:"src,omitempty"`
-	Type  string `json:"type,omitempty"`
-	Value string `json:"value,omitempty"`
+	Src    string `json:"src,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Value  string `json:"value,omitempty"`
+	Base64 string `json:"base64,omitempty"`
+}
+
+// Decode returns the decoded bytes of base64 encoded content.
+func (c *Content) Decode() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(c.Base64)
 }
 
 // Generator identifies the agent used to generate a
@@ -112,3 +129,31 @@ type Source struct {
 	Categories    []*Category    `json:"categories,omitempty"`
 	Extensions    ext.Extensions `json:"extensions,omitempty"`
 }
+
+// ParseWarning describes a non-fatal issue that was
+// encountered while parsing the feed
+type ParseWarning struct {
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+func (w ParseWarning) String() string {
+	return w.Path + ": " + w.Message
+}
+
+// ParseError is returned when a feed can not be parsed. It
+// records where in the document parsing stopped.
+type ParseError struct {
+	Offset int64  // Byte offset into the document
+	Line   int    // Line number, starting at 1
+	Path   string // Element path (e.g. "feed > entry[3] > updated")
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d, offset %d: %s: %v", e.Line, e.Offset, e.Path, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
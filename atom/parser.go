@@ -2,6 +2,8 @@ package atom
 
 import (
 	"encoding/base64"
+	"encoding/xml"
+	"fmt"
 	"io"
 	"strings"
 
@@ -23,19 +25,169 @@ var (
 	}
 )
 
+// Namespace of the Atom threading extension, RFC 4685
+const threadingNS = "http://purl.org/syndication/thread/1.0"
+
+// ErrLimitExceeded is wrapped by the error returned when a
+// feed goes over one of the parser's limits.
+var ErrLimitExceeded = shared.ErrLimitExceeded
+
 // Parser is an Atom Parser
-type Parser struct{}
+type Parser struct {
+	// Limits guarding against hostile feeds. Zero values
+	// fall back to generous defaults, negative values
+	// disable the limit.
+	MaxItems        int   // Maximum number of entries
+	MaxElementDepth int   // Maximum nesting of elements
+	MaxBytes        int64 // Maximum size of the document
+
+	// DisableExtensions skips extension elements instead of
+	// collecting them, which saves the allocations of building
+	// their trees. Extensions are then left nil.
+	DisableExtensions bool
+
+	// Charset overrides the encoding named by the XML
+	// declaration, e.g. with the charset of the HTTP response
+	// the feed came from. "utf-8" parses the input as it is,
+	// any other label known to golang.org/x/net/html/charset
+	// decodes the input from that encoding.
+	Charset string
+
+	// Strict rejects documents that aren't well-formed XML,
+	// e.g. with undefined entities or mismatched tags, instead
+	// of parsing them leniently.
+	Strict bool
+
+	// MetadataOnly parses the feed but skips over the entries
+	// without parsing them, only counting them in
+	// Feed.EntryCount. Feed.Entries is left empty.
+	MetadataOnly bool
+
+	// State for a single call to Parse. It is only ever
+	// set on the copy of the Parser made by Parse.
+	limits       shared.Limits
+	prefixes     shared.Prefixes
+	path         shared.ElementPath
+	entries      int
+	warnings     []ParseWarning
+	entryHandler func(*Entry) error
+}
 
 // Parse parses an xml feed into an atom.Feed
 func (ap *Parser) Parse(feed io.Reader) (*Feed, error) {
-	p := xpp.NewXMLPullParser(feed, false, shared.NewReaderLabel)
-
-	_, err := shared.FindRoot(p)
+	// Parse using a copy of the parser so the per-parse state
+	// is never shared between concurrent calls to Parse.
+	s := *ap
+	s.limits = shared.NewLimits(s.MaxItems, s.MaxElementDepth, s.MaxBytes)
+	s.prefixes = shared.Prefixes{}
+
+	pr := shared.NewPositionReader(feed, s.limits.MaxBytes)
+	defer pr.Release()
+	r, cr, err := shared.DecodeCharset(s.Charset, pr)
 	if err != nil {
 		return nil, err
 	}
+	p := xpp.NewXMLPullParser(r, s.Strict, cr)
+
+	_, err = shared.FindRoot(p)
+	if err != nil {
+		return nil, s.parseError(p, pr, err)
+	}
+
+	result, err := s.parseRoot(p)
+	if err != nil {
+		return nil, s.parseError(p, pr, err)
+	}
+	return result, nil
+}
+
+// ParseStream parses an xml feed like Parse, but hands each
+// entry to handler as soon as it has been parsed instead of
+// collecting them in Feed.Entries, so memory use doesn't grow
+// with the number of entries. An error returned by handler
+// aborts parsing and is returned wrapped in a ParseError.
+//
+// The returned Feed holds the feed metadata only, since it may
+// follow the entries in the document. Duplicate ids aren't
+// reported as the entries are not retained.
+func (ap *Parser) ParseStream(feed io.Reader, handler func(*Entry) error) (*Feed, error) {
+	s := *ap
+	s.entryHandler = handler
+	return s.Parse(feed)
+}
+
+// collectEntry hands entry to the stream handler, or appends
+// it to entries when not streaming.
+func (ap *Parser) collectEntry(entries []*Entry, entry *Entry) ([]*Entry, error) {
+	if ap.entryHandler == nil {
+		return append(entries, entry), nil
+	}
+	return entries, ap.entryHandler(entry)
+}
+
+// parseError wraps err with the position in the document and
+// the path of the element being parsed when it occurred. The
+// path is only popped when an element parses successfully, so
+// on failure it still points at the innermost element.
+func (ap *Parser) parseError(p *xpp.XMLPullParser, pr *shared.PositionReader, err error) error {
+	child := ""
+	if p.Depth > ap.path.Depth() {
+		child = p.Name
+	}
+	offset, line := pr.Position(err)
+	return &ParseError{
+		Offset: offset,
+		Line:   line,
+		Path:   ap.path.Render(child),
+		Err:    err,
+	}
+}
 
-	return ap.parseRoot(p)
+// warn records a non-fatal issue found in the given
+// child element of the element currently being parsed.
+func (ap *Parser) warn(element string, format string, args ...interface{}) {
+	ap.warnings = append(ap.warnings, ParseWarning{
+		Path:    ap.path.Render(element),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// Namespaces of Atom 0.3 and 1.0
+const (
+	atom03Namespace = "http://purl.org/atom/ns#"
+	atom10Namespace = "http://www.w3.org/2005/Atom"
+)
+
+// isExtension reports whether the element the parser is on
+// is an extension element. Elements in the Atom namespace
+// never are, even when the feed binds it to a prefix.
+func (ap *Parser) isExtension(p *xpp.XMLPullParser) bool {
+	switch strings.TrimSpace(p.Space) {
+	case atom03Namespace, atom10Namespace:
+		return false
+	}
+	return ap.prefixes.IsExtension(p)
+}
+
+// parseExtension adds the extension element the parser is
+// on to extensions, or skips it if extensions are disabled.
+func (ap *Parser) parseExtension(extensions ext.Extensions, p *xpp.XMLPullParser) (ext.Extensions, error) {
+	if ap.DisableExtensions {
+		return extensions, p.Skip()
+	}
+	return ap.limits.ParseExtension(extensions, p, ap.prefixes)
+}
+
+// warnDuplicateIDs records a warning for every entry
+// whose id was already used by an earlier entry.
+func (ap *Parser) warnDuplicateIDs(entries []*Entry) {
+	id := func(i int) string { return entries[i].ID }
+	shared.FindDuplicates(len(entries), id, func(first, dup int) {
+		ap.warnings = append(ap.warnings, ParseWarning{
+			Path:    ap.path.Render(fmt.Sprintf("entry[%d] > id", dup)),
+			Message: fmt.Sprintf("duplicate id %q also used by entry[%d]", id(dup), first),
+		})
+	})
 }
 
 func (ap *Parser) parseRoot(p *xpp.XMLPullParser) (*Feed, error) {
@@ -47,15 +199,19 @@ func (ap *Parser) parseRoot(p *xpp.XMLPullParser) (*Feed, error) {
 	atom.Entries = []*Entry{}
 	atom.Version = ap.parseVersion(p)
 	atom.Language = ap.parseLanguage(p)
+	atom.Namespaces = shared.DeclaredNamespaces(p)
+
+	ap.path.Push(p.Name, -1)
 
 	contributors := []*Person{}
 	authors := []*Person{}
 	categories := []*Category{}
 	links := []*Link{}
-	extensions := ext.Extensions{}
+	extensions := shared.GetExtensions()
+	defer shared.PutExtensions(extensions)
 
 	for {
-		tok, err := shared.NextTag(p)
+		tok, err := ap.limits.NextTag(p)
 		if err != nil {
 			return nil, err
 		}
@@ -68,8 +224,8 @@ func (ap *Parser) parseRoot(p *xpp.XMLPullParser) (*Feed, error) {
 
 			name := strings.ToLower(p.Name)
 
-			if shared.IsExtension(p) {
-				e, err := shared.ParseExtension(extensions, p)
+			if ap.isExtension(p) {
+				e, err := ap.parseExtension(extensions, p)
 				if err != nil {
 					return nil, err
 				}
@@ -97,6 +253,22 @@ func (ap *Parser) parseRoot(p *xpp.XMLPullParser) (*Feed, error) {
 				if err == nil {
 					utcDate := date.UTC()
 					atom.UpdatedParsed = &utcDate
+				} else {
+					ap.warn(p.Name, "unparseable date %q", result)
+				}
+			} else if name == "published" ||
+				name == "issued" {
+				result, err := ap.parseAtomText(p)
+				if err != nil {
+					return nil, err
+				}
+				atom.Published = result
+				date, err := shared.ParseDate(result)
+				if err == nil {
+					utcDate := date.UTC()
+					atom.PublishedParsed = &utcDate
+				} else {
+					ap.warn(p.Name, "unparseable date %q", result)
 				}
 			} else if name == "subtitle" ||
 				name == "tagline" {
@@ -154,13 +326,27 @@ func (ap *Parser) parseRoot(p *xpp.XMLPullParser) (*Feed, error) {
 					return nil, err
 				}
 				categories = append(categories, result)
+			} else if name == "entry" && ap.MetadataOnly {
+				if err := ap.skipEntry(p); err != nil {
+					return nil, err
+				}
 			} else if name == "entry" {
 				result, err := ap.parseEntry(p)
 				if err != nil {
 					return nil, err
 				}
-				atom.Entries = append(atom.Entries, result)
+				if atom.Entries, err = ap.collectEntry(atom.Entries, result); err != nil {
+					return nil, err
+				}
+			} else if name == "info" {
+				// Atom 0.3 description of the feed format
+				// meant for people viewing the raw feed.
+				err := p.Skip()
+				if err != nil {
+					return nil, err
+				}
 			} else {
+				ap.warn(p.Name, "unexpected element skipped")
 				err := p.Skip()
 				if err != nil {
 					return nil, err
@@ -193,23 +379,47 @@ func (ap *Parser) parseRoot(p *xpp.XMLPullParser) (*Feed, error) {
 		return nil, err
 	}
 
+	ap.warnDuplicateIDs(atom.Entries)
+	if ap.MetadataOnly {
+		atom.EntryCount = ap.entries
+	}
+	atom.Warnings = ap.warnings
+	ap.path.Pop()
 	return atom, nil
 }
 
+// skipEntry counts an entry without parsing it.
+func (ap *Parser) skipEntry(p *xpp.XMLPullParser) error {
+	ap.entries++
+	if err := ap.limits.CheckItems(ap.entries); err != nil {
+		return err
+	}
+	return p.Skip()
+}
+
 func (ap *Parser) parseEntry(p *xpp.XMLPullParser) (*Entry, error) {
 	if err := p.Expect(xpp.StartTag, "entry"); err != nil {
 		return nil, err
 	}
+	ap.path.Push("entry", ap.entries)
+	ap.entries++
+	if err := ap.limits.CheckItems(ap.entries); err != nil {
+		return nil, err
+	}
+
 	entry := &Entry{}
+	entry.Language = ap.parseLanguage(p)
 
 	contributors := []*Person{}
 	authors := []*Person{}
 	categories := []*Category{}
 	links := []*Link{}
-	extensions := ext.Extensions{}
+	created := ""
+	extensions := shared.GetExtensions()
+	defer shared.PutExtensions(extensions)
 
 	for {
-		tok, err := shared.NextTag(p)
+		tok, err := ap.limits.NextTag(p)
 		if err != nil {
 			return nil, err
 		}
@@ -222,8 +432,8 @@ func (ap *Parser) parseEntry(p *xpp.XMLPullParser) (*Entry, error) {
 
 			name := strings.ToLower(p.Name)
 
-			if shared.IsExtension(p) {
-				e, err := shared.ParseExtension(extensions, p)
+			if ap.isExtension(p) {
+				e, err := ap.parseExtension(extensions, p)
 				if err != nil {
 					return nil, err
 				}
@@ -248,6 +458,7 @@ func (ap *Parser) parseEntry(p *xpp.XMLPullParser) (*Entry, error) {
 				}
 				entry.Rights = result
 			} else if name == "summary" {
+				entry.SummaryType = p.Attribute("type")
 				result, err := ap.parseAtomText(p)
 				if err != nil {
 					return nil, err
@@ -270,6 +481,8 @@ func (ap *Parser) parseEntry(p *xpp.XMLPullParser) (*Entry, error) {
 				if err == nil {
 					utcDate := date.UTC()
 					entry.UpdatedParsed = &utcDate
+				} else {
+					ap.warn(p.Name, "unparseable date %q", result)
 				}
 			} else if name == "contributor" {
 				result, err := ap.parsePerson("contributor", p)
@@ -295,6 +508,14 @@ func (ap *Parser) parseEntry(p *xpp.XMLPullParser) (*Entry, error) {
 					return nil, err
 				}
 				links = append(links, result)
+			} else if name == "created" {
+				// Atom 0.3 creation date, only used when
+				// the entry has no issued date.
+				result, err := ap.parseAtomText(p)
+				if err != nil {
+					return nil, err
+				}
+				created = result
 			} else if name == "published" ||
 				name == "issued" {
 				result, err := ap.parseAtomText(p)
@@ -306,6 +527,8 @@ func (ap *Parser) parseEntry(p *xpp.XMLPullParser) (*Entry, error) {
 				if err == nil {
 					utcDate := date.UTC()
 					entry.PublishedParsed = &utcDate
+				} else {
+					ap.warn(p.Name, "unparseable date %q", result)
 				}
 			} else if name == "content" {
 				result, err := ap.parseContent(p)
@@ -314,6 +537,7 @@ func (ap *Parser) parseEntry(p *xpp.XMLPullParser) (*Entry, error) {
 				}
 				entry.Content = result
 			} else {
+				ap.warn(p.Name, "unexpected element skipped")
 				err := p.Skip()
 				if err != nil {
 					return nil, err
@@ -338,6 +562,17 @@ func (ap *Parser) parseEntry(p *xpp.XMLPullParser) (*Entry, error) {
 		entry.Contributors = contributors
 	}
 
+	if entry.Published == "" && created != "" {
+		entry.Published = created
+		date, err := shared.ParseDate(created)
+		if err == nil {
+			utcDate := date.UTC()
+			entry.PublishedParsed = &utcDate
+		} else {
+			ap.warn("created", "unparseable date %q", created)
+		}
+	}
+
 	if len(extensions) > 0 {
 		entry.Extensions = extensions
 	}
@@ -346,6 +581,7 @@ func (ap *Parser) parseEntry(p *xpp.XMLPullParser) (*Entry, error) {
 		return nil, err
 	}
 
+	ap.path.Pop()
 	return entry, nil
 }
 
@@ -355,16 +591,19 @@ func (ap *Parser) parseSource(p *xpp.XMLPullParser) (*Source, error) {
 		return nil, err
 	}
 
+	ap.path.Push("source", -1)
+
 	source := &Source{}
 
 	contributors := []*Person{}
 	authors := []*Person{}
 	categories := []*Category{}
 	links := []*Link{}
-	extensions := ext.Extensions{}
+	extensions := shared.GetExtensions()
+	defer shared.PutExtensions(extensions)
 
 	for {
-		tok, err := shared.NextTag(p)
+		tok, err := ap.limits.NextTag(p)
 		if err != nil {
 			return nil, err
 		}
@@ -377,8 +616,8 @@ func (ap *Parser) parseSource(p *xpp.XMLPullParser) (*Source, error) {
 
 			name := strings.ToLower(p.Name)
 
-			if shared.IsExtension(p) {
-				e, err := shared.ParseExtension(extensions, p)
+			if ap.isExtension(p) {
+				e, err := ap.parseExtension(extensions, p)
 				if err != nil {
 					return nil, err
 				}
@@ -406,6 +645,8 @@ func (ap *Parser) parseSource(p *xpp.XMLPullParser) (*Source, error) {
 				if err == nil {
 					utcDate := date.UTC()
 					source.UpdatedParsed = &utcDate
+				} else {
+					ap.warn(p.Name, "unparseable date %q", result)
 				}
 			} else if name == "subtitle" ||
 				name == "tagline" {
@@ -464,6 +705,7 @@ func (ap *Parser) parseSource(p *xpp.XMLPullParser) (*Source, error) {
 				}
 				categories = append(categories, result)
 			} else {
+				ap.warn(p.Name, "unexpected element skipped")
 				err := p.Skip()
 				if err != nil {
 					return nil, err
@@ -496,6 +738,7 @@ func (ap *Parser) parseSource(p *xpp.XMLPullParser) (*Source, error) {
 		return nil, err
 	}
 
+	ap.path.Pop()
 	return source, nil
 }
 
@@ -504,6 +747,37 @@ func (ap *Parser) parseContent(p *xpp.XMLPullParser) (*Content, error) {
 	c.Type = p.Attribute("type")
 	c.Src = p.Attribute("src")
 
+	// Out of line content leaves the element empty, there
+	// is no body to read. The src is never fetched.
+	if c.Src != "" {
+		base := p.BaseStack.Top()
+		resolved, err := shared.XmlBaseResolveUrl(base, c.Src)
+		if resolved != nil && err == nil {
+			c.Src = resolved.String()
+		}
+		if err := p.Skip(); err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+
+	if isBinaryMediaType(c.Type) {
+		var body struct {
+			InnerXML string `xml:",innerxml"`
+		}
+		if err := p.DecodeElement(&body); err != nil {
+			return nil, err
+		}
+		c.Base64 = strings.Join(strings.Fields(body.InnerXML), "")
+		decoded, err := c.Decode()
+		if err == nil {
+			c.Value = string(decoded)
+		} else {
+			c.Value = c.Base64
+		}
+		return c, nil
+	}
+
 	text, err := ap.parseAtomText(p)
 	if err != nil {
 		return nil, err
@@ -513,6 +787,28 @@ func (ap *Parser) parseContent(p *xpp.XMLPullParser) (*Content, error) {
 	return c, nil
 }
 
+// isBinaryMediaType reports whether content of the given
+// type is base64 encoded, which is the case for any media
+// type that is neither textual nor XML.
+// https://tools.ietf.org/html/rfc4287#section-4.1.3.3
+func isBinaryMediaType(mediaType string) bool {
+	t := strings.ToLower(strings.TrimSpace(mediaType))
+	if t == "" || t == "text" || t == "html" || t == "xhtml" {
+		return false
+	}
+	if i := strings.Index(t, ";"); i >= 0 {
+		t = strings.TrimSpace(t[:i])
+	}
+	if !strings.Contains(t, "/") {
+		return false
+	}
+	return !(strings.HasPrefix(t, "text/") ||
+		strings.HasSuffix(t, "+xml") ||
+		strings.HasSuffix(t, "/xml") ||
+		strings.HasSuffix(t, "/xml-external-parsed-entity") ||
+		strings.HasSuffix(t, "/xml-dtd"))
+}
+
 func (ap *Parser) parsePerson(name string, p *xpp.XMLPullParser) (*Person, error) {
 
 	if err := p.Expect(xpp.StartTag, name); err != nil {
@@ -522,7 +818,7 @@ func (ap *Parser) parsePerson(name string, p *xpp.XMLPullParser) (*Person, error
 	person := &Person{}
 
 	for {
-		tok, err := shared.NextTag(p)
+		tok, err := ap.limits.NextTag(p)
 		if err != nil {
 			return nil, err
 		}
@@ -583,6 +879,7 @@ func (ap *Parser) parseLink(p *xpp.XMLPullParser) (*Link, error) {
 	l.Length = p.Attribute("length")
 	l.Title = p.Attribute("title")
 	l.Rel = p.Attribute("rel")
+	l.Count = shared.AttributeNS(p, threadingNS, "count")
 	if l.Rel == "" {
 		l.Rel = "alternate"
 	}
@@ -684,7 +981,7 @@ func (ap *Parser) parseAtomText(p *xpp.XMLPullParser) (string, error) {
 			(lowerType == "" && lowerMode == "") {
 			result, err = shared.DecodeEntities(result)
 		} else if strings.Contains(lowerType, "xhtml") {
-			result = ap.stripWrappingDiv(result)
+			result = ap.unwrapXHTMLDiv(result)
 			result, _ = shared.ResolveHTML(base, result)
 		} else if lowerType == "html" {
 			result = ap.stripWrappingDiv(result)
@@ -722,12 +1019,14 @@ func (ap *Parser) parseVersion(p *xpp.XMLPullParser) string {
 		return ver
 	}
 
-	ns := p.Attribute("xmlns")
-	if ns == "http://purl.org/atom/ns#" {
+	// The namespace of the root element, whether it is
+	// the default namespace or bound to a prefix
+	ns := strings.TrimSpace(p.Space)
+	if ns == atom03Namespace {
 		return "0.3"
 	}
 
-	if ns == "http://www.w3.org/2005/Atom" {
+	if ns == atom10Namespace {
 		return "1.0"
 	}
 
@@ -749,3 +1048,61 @@ func (ap *Parser) stripWrappingDiv(content string) (result string) {
 	}
 	return
 }
+
+// unwrapXHTMLDiv returns the serialized children of the
+// <div> that wraps xhtml constructs. The markup is sliced
+// out of the source rather than re-rendered so it stays
+// well formed XHTML. A namespace prefix used on the div
+// (e.g. <xhtml:div>) is dropped from the inner elements.
+func (ap *Parser) unwrapXHTMLDiv(content string) string {
+	d := xml.NewDecoder(strings.NewReader(content))
+	d.Strict = false
+
+	var (
+		prefix     string
+		innerStart int64 = -1
+		innerEnd   int64 = -1
+		depth      int
+	)
+	for {
+		offset := d.InputOffset()
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return ap.stripWrappingDiv(content)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				if innerStart >= 0 || !strings.EqualFold(t.Name.Local, "div") {
+					return content
+				}
+				prefix = t.Name.Space
+				innerStart = d.InputOffset()
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+			if depth == 0 {
+				innerEnd = offset
+			}
+		case xml.CharData:
+			if depth == 0 && len(strings.TrimSpace(string(t))) > 0 {
+				return content
+			}
+		}
+	}
+
+	if innerStart < 0 || innerEnd < innerStart {
+		return content
+	}
+
+	inner := strings.TrimSpace(content[innerStart:innerEnd])
+	if prefix != "" {
+		inner = strings.NewReplacer("<"+prefix+":", "<", "</"+prefix+":", "</").Replace(inner)
+	}
+	return inner
+}
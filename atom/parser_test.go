@@ -3,6 +3,7 @@ package atom_test
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -47,4 +48,59 @@ func TestParser_Parse(t *testing.T) {
 	}
 }
 
+func TestParser_ParseError(t *testing.T) {
+	feed := "<feed xmlns=\"http://www.w3.org/2005/Atom\">\n<entry>\n<title a=\"b>x</title>\n</entry>\n</feed>"
+
+	fp := &atom.Parser{}
+	_, err := fp.Parse(strings.NewReader(feed))
+
+	var perr *atom.ParseError
+	if assert.ErrorAs(t, err, &perr) {
+		assert.Equal(t, 3, perr.Line)
+		assert.Equal(t, "feed > entry[0]", perr.Path)
+	}
+}
+
+func TestParser_ParseStream(t *testing.T) {
+	feed := `<feed xmlns="http://www.w3.org/2005/Atom"><entry><title>One</title></entry>
+<entry><title>Two</title></entry><title>Feed</title><entry><title>Three</title></entry></feed>`
+
+	fp := &atom.Parser{}
+	titles := []string{}
+	actual, err := fp.ParseStream(strings.NewReader(feed), func(entry *atom.Entry) error {
+		titles = append(titles, entry.Title)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"One", "Two", "Three"}, titles)
+	assert.Equal(t, "Feed", actual.Title)
+	assert.Empty(t, actual.Entries)
+
+	stop := errors.New("stop")
+	titles = []string{}
+	_, err = fp.ParseStream(strings.NewReader(feed), func(entry *atom.Entry) error {
+		titles = append(titles, entry.Title)
+		return stop
+	})
+
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, []string{"One"}, titles)
+}
+
+func TestParser_DisableExtensions(t *testing.T) {
+	feed := `<feed xmlns="http://www.w3.org/2005/Atom" xmlns:x="http://example.org/x">
+<x:a><x:b>1</x:b></x:a><title>Feed</title>
+<entry><x:a>2</x:a><title>Entry</title></entry></feed>`
+
+	fp := &atom.Parser{DisableExtensions: true}
+	actual, err := fp.Parse(strings.NewReader(feed))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Feed", actual.Title)
+	assert.Nil(t, actual.Extensions)
+	assert.Equal(t, "Entry", actual.Entries[0].Title)
+	assert.Nil(t, actual.Entries[0].Extensions)
+}
+
 // TODO: Examples
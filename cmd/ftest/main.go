@@ -59,7 +59,11 @@ func main() {
 			os.Exit(1)
 		}
 
-		fmt.Println(feed)
+		if f, ok := feed.(*gofeed.Feed); ok {
+			fmt.Println(f.Dump())
+		} else {
+			fmt.Println(feed)
+		}
 	}
 	app.Run(os.Args)
 }
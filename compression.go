@@ -0,0 +1,120 @@
+package gofeed
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Magic number every gzip stream starts with.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// decompressBody undoes the Content-Encoding of a response
+// body. Encodings it doesn't know are left alone. A body that
+// still starts like a gzip stream afterwards, e.g. a .gz file
+// or a response compressed twice, is decompressed once more.
+// Go's transport already removes the gzip encoding it asked
+// for itself, in which case contentEncoding is empty.
+func decompressBody(body io.Reader, contentEncoding string) (io.Reader, error) {
+	encodings := strings.Split(contentEncoding, ",")
+	for i := len(encodings) - 1; i >= 0; i-- {
+		var err error
+		switch enc := strings.ToLower(strings.TrimSpace(encodings[i])); enc {
+		case "gzip", "x-gzip":
+			body, err = newDecompressReader(enc, body, gunzip)
+		case "deflate":
+			body, err = newDecompressReader(enc, body, inflate)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	br := bufio.NewReader(body)
+	if magic, _ := br.Peek(len(gzipMagic)); bytes.Equal(magic, gzipMagic) {
+		return newDecompressReader("gzip", br, gunzip)
+	}
+	return br, nil
+}
+
+func gunzip(r io.Reader) (io.Reader, error) {
+	return gzip.NewReader(r)
+}
+
+// inflate decompresses the zlib stream that HTTP's deflate
+// stands for, or a raw deflate stream as some servers send.
+func inflate(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+	if header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(br)
+	}
+	return flate.NewReader(br), nil
+}
+
+// decompressReader reads a compressed body, wrapping the
+// errors of a corrupt stream so they say what went wrong.
+type decompressReader struct {
+	encoding string
+	r        io.Reader
+}
+
+func newDecompressReader(encoding string, body io.Reader, decompress func(io.Reader) (io.Reader, error)) (io.Reader, error) {
+	d := &decompressReader{encoding: encoding}
+	r, err := decompress(body)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, d.wrap(err)
+	}
+	d.r = r
+	return d, nil
+}
+
+func (d *decompressReader) Read(b []byte) (int, error) {
+	n, err := d.r.Read(b)
+	return n, d.wrap(err)
+}
+
+func (d *decompressReader) wrap(err error) error {
+	if err == nil || err == io.EOF || errors.Is(err, ErrResponseTooLarge) {
+		return err
+	}
+	if err == io.ErrUnexpectedEOF {
+		err = fmt.Errorf("truncated stream: %w", err)
+	}
+	return fmt.Errorf("corrupt %s response body: %w", d.encoding, err)
+}
+
+// limitedReader fails with err once more than n bytes have
+// been read from r.
+type limitedReader struct {
+	r   io.Reader
+	n   int64
+	err error
+}
+
+func (l *limitedReader) Read(b []byte) (int, error) {
+	if l.n < 0 {
+		return 0, l.err
+	}
+	if int64(len(b)) > l.n+1 {
+		b = b[:l.n+1]
+	}
+	n, err := l.r.Read(b)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return 0, l.err
+	}
+	return n, err
+}
@@ -30,8 +30,16 @@ const (
 // by looking for specific xml elements unique to the
 // various feed types.
 func DetectFeedType(feed io.Reader) FeedType {
-	buffer := new(bytes.Buffer)
+	buffer := shared.GetBuffer()
+	defer shared.PutBuffer(buffer)
 	buffer.ReadFrom(feed)
+	return detectFeedType(buffer.Bytes())
+}
+
+// detectFeedType is DetectFeedType for a feed that
+// has already been read into memory.
+func detectFeedType(feed []byte) FeedType {
+	buffer := bytes.NewBuffer(feed)
 
 	var firstChar byte
 	loop: for {
@@ -52,7 +60,7 @@ func DetectFeedType(feed io.Reader) FeedType {
 
 	if firstChar == '<' {
 		// Check if it's an XML based feed
-		p := xpp.NewXMLPullParser(bytes.NewReader(buffer.Bytes()), false, shared.NewReaderLabel)
+		p := xpp.NewXMLPullParser(bytes.NewReader(buffer.Bytes()), false, detectCharsetReader)
 
 		_, err := shared.FindRoot(p)
 		if err != nil {
@@ -78,3 +86,15 @@ func DetectFeedType(feed io.Reader) FeedType {
 	}
 	return FeedTypeUnknown
 }
+
+// detectCharsetReader decodes the document like the feed
+// parsers do, except that an unsupported encoding leaves it
+// as it is. Only the name of the root element is looked at,
+// which doesn't depend on the encoding of the rest. The feed
+// may still be parsed if the caller overrides its charset.
+func detectCharsetReader(label string, input io.Reader) (io.Reader, error) {
+	if r, err := shared.NewReaderLabel(label, input); err == nil {
+		return r, nil
+	}
+	return input, nil
+}
@@ -0,0 +1,97 @@
+package gofeed
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Media types of <link rel="alternate"> elements that point
+// at feeds, from most to least preferred.
+var discoveryLinkTypes = []string{
+	"application/atom+xml",
+	"application/rss+xml",
+	"application/feed+json",
+	"application/json",
+}
+
+// DiscoveryError is returned when an HTML page links to more
+// than one feed and none of them is clearly the best choice.
+type DiscoveryError struct {
+	URL        string
+	Candidates []string
+}
+
+func (e *DiscoveryError) Error() string {
+	return fmt.Sprintf("%s links to %d feeds, parse one of them directly: %s",
+		e.URL, len(e.Candidates), strings.Join(e.Candidates, ", "))
+}
+
+// discoverFeedURL looks for the feed advertised by an HTML
+// page. It returns an empty string when the page doesn't
+// advertise any feed.
+func discoverFeedURL(page []byte, location *url.URL) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page))
+	if err != nil {
+		return "", nil
+	}
+
+	// Candidates grouped by their index in discoveryLinkTypes
+	candidates := make([][]string, len(discoveryLinkTypes))
+	seen := map[string]bool{}
+
+	doc.Find("link[href]").Each(func(_ int, link *goquery.Selection) {
+		rel, _ := link.Attr("rel")
+		if !hasToken(rel, "alternate") {
+			return
+		}
+
+		linkType, _ := link.Attr("type")
+		rank := -1
+		for i, t := range discoveryLinkTypes {
+			if strings.EqualFold(strings.TrimSpace(linkType), t) {
+				rank = i
+				break
+			}
+		}
+		if rank < 0 {
+			return
+		}
+
+		href, _ := link.Attr("href")
+		ref, err := url.Parse(strings.TrimSpace(href))
+		if err != nil {
+			return
+		}
+		resolved := location.ResolveReference(ref).String()
+		if seen[resolved] {
+			return
+		}
+		seen[resolved] = true
+		candidates[rank] = append(candidates[rank], resolved)
+	})
+
+	for _, best := range candidates {
+		if len(best) == 1 {
+			return best[0], nil
+		}
+		if len(best) > 1 {
+			return "", &DiscoveryError{URL: location.String(), Candidates: best}
+		}
+	}
+	return "", nil
+}
+
+// hasToken reports whether the space separated
+// list of tokens contains token.
+func hasToken(list, token string) bool {
+	for _, t := range strings.Fields(list) {
+		if strings.EqualFold(t, token) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+package gofeed
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	ext "github.com/mmcdole/gofeed/extensions"
+)
+
+// Number of item titles included in Feed.String.
+const summaryItemTitles = 3
+
+// String returns a short human readable summary of the
+// feed: its title, type, version and the first few item
+// titles. Use Dump to see everything that was parsed.
+func (f Feed) String() string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "%q (%s %s) with %d items", f.Title, f.FeedType, f.FeedVersion, len(f.Items))
+	for i, item := range f.Items {
+		if i == summaryItemTitles {
+			fmt.Fprintf(b, "\n  ... and %d more", len(f.Items)-summaryItemTitles)
+			break
+		}
+		fmt.Fprintf(b, "\n  - %q", item.Title)
+	}
+	return b.String()
+}
+
+// Dump renders the feed, its items and their extensions
+// as an indented tree for debugging. The output is meant
+// for humans and its format may change at any time.
+func (f Feed) Dump() string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "feed %s %s\n", f.FeedType, f.FeedVersion)
+	dumpField(b, 1, "title", f.Title)
+	dumpField(b, 1, "description", f.Description)
+	dumpField(b, 1, "link", f.Link)
+	dumpField(b, 1, "feedLink", f.FeedLink)
+	dumpList(b, 1, "hubs", f.Hubs)
+	dumpList(b, 1, "links", f.Links)
+	dumpField(b, 1, "id", f.ID)
+	dumpField(b, 1, "updated", f.Updated)
+	dumpField(b, 1, "published", f.Published)
+	dumpPersons(b, 1, f.Authors)
+	dumpField(b, 1, "language", f.Language)
+	dumpImage(b, 1, f.Image)
+	dumpField(b, 1, "copyright", f.Copyright)
+	dumpField(b, 1, "generator", f.Generator)
+	dumpList(b, 1, "categories", f.Categories)
+	if f.TTL != 0 {
+		dumpField(b, 1, "ttl", f.TTL.String())
+	}
+	dumpCustom(b, 1, f.Custom)
+	dumpExtensions(b, 1, f.Extensions)
+	for _, w := range f.Warnings {
+		dumpField(b, 1, "warning", w.String())
+	}
+	for i, item := range f.Items {
+		fmt.Fprintf(b, "  item[%d]\n", i)
+		dumpItem(b, 2, item)
+	}
+	return b.String()
+}
+
+func dumpItem(b *strings.Builder, depth int, item *Item) {
+	dumpField(b, depth, "title", item.Title)
+	dumpField(b, depth, "description", item.Description)
+	dumpField(b, depth, "content", item.Content)
+	dumpField(b, depth, "contentType", item.ContentType)
+	dumpField(b, depth, "link", item.Link)
+	dumpList(b, depth, "links", item.Links)
+	dumpField(b, depth, "updated", item.Updated)
+	dumpField(b, depth, "published", item.Published)
+	dumpPersons(b, depth, item.Authors)
+	dumpField(b, depth, "guid", item.GUID)
+	dumpImage(b, depth, item.Image)
+	dumpField(b, depth, "copyright", item.Copyright)
+	dumpList(b, depth, "categories", item.Categories)
+	for _, enc := range item.Enclosures {
+		dumpField(b, depth, "enclosure", fmt.Sprintf("%s (%s, %s bytes)", enc.URL, enc.Type, enc.Length))
+	}
+	dumpCustom(b, depth, item.Custom)
+	dumpExtensions(b, depth, item.Extensions)
+}
+
+func dumpField(b *strings.Builder, depth int, name, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Fprintf(b, "%s%s: %q\n", strings.Repeat("  ", depth), name, value)
+}
+
+func dumpList(b *strings.Builder, depth int, name string, values []string) {
+	for _, v := range values {
+		dumpField(b, depth, name, v)
+	}
+}
+
+func dumpPersons(b *strings.Builder, depth int, persons []*Person) {
+	for _, p := range persons {
+		if p.Email != "" {
+			dumpField(b, depth, "author", fmt.Sprintf("%s <%s>", p.Name, p.Email))
+		} else {
+			dumpField(b, depth, "author", p.Name)
+		}
+	}
+}
+
+func dumpImage(b *strings.Builder, depth int, image *Image) {
+	if image == nil {
+		return
+	}
+	dumpField(b, depth, "image", image.URL)
+}
+
+func dumpCustom(b *strings.Builder, depth int, custom map[string]string) {
+	for _, k := range sortedKeys(custom) {
+		dumpField(b, depth, k, custom[k])
+	}
+}
+
+func dumpExtensions(b *strings.Builder, depth int, extensions ext.Extensions) {
+	prefixes := make([]string, 0, len(extensions))
+	for prefix := range extensions {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+
+	for _, prefix := range prefixes {
+		dumpExtensionElements(b, depth, prefix, extensions[prefix])
+	}
+}
+
+func dumpExtensionElements(b *strings.Builder, depth int, prefix string, elements map[string][]ext.Extension) {
+	names := make([]string, 0, len(elements))
+	for name := range elements {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	indent := strings.Repeat("  ", depth)
+	for _, name := range names {
+		for _, e := range elements[name] {
+			fmt.Fprintf(b, "%s%s:%s", indent, prefix, name)
+			for _, k := range sortedKeys(e.Attrs) {
+				fmt.Fprintf(b, " %s=%q", k, e.Attrs[k])
+			}
+			if value := strings.TrimSpace(e.Value); value != "" {
+				fmt.Fprintf(b, " %q", value)
+			}
+			b.WriteString("\n")
+			dumpExtensionElements(b, depth+1, prefix, e.Children)
+		}
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
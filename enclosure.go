@@ -0,0 +1,72 @@
+package gofeed
+
+import (
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Media types of common enclosure file extensions. They take
+// precedence over mime.TypeByExtension, whose answers depend
+// on the system's mime tables.
+var enclosureTypes = map[string]string{
+	".aac":  "audio/aac",
+	".epub": "application/epub+zip",
+	".flac": "audio/flac",
+	".gif":  "image/gif",
+	".jpeg": "image/jpeg",
+	".jpg":  "image/jpeg",
+	".m4a":  "audio/mp4",
+	".m4b":  "audio/mp4",
+	".m4v":  "video/x-m4v",
+	".mov":  "video/quicktime",
+	".mp3":  "audio/mpeg",
+	".mp4":  "video/mp4",
+	".oga":  "audio/ogg",
+	".ogg":  "audio/ogg",
+	".ogv":  "video/ogg",
+	".opus": "audio/opus",
+	".pdf":  "application/pdf",
+	".png":  "image/png",
+	".wav":  "audio/wav",
+	".webm": "video/webm",
+	".webp": "image/webp",
+}
+
+// inferEnclosureTypes fills in the type of enclosures that
+// don't have one, see Parser.InferEnclosureTypes.
+func inferEnclosureTypes(feed *Feed) {
+	for _, item := range feed.Items {
+		for _, enc := range item.Enclosures {
+			if strings.TrimSpace(enc.Type) != "" {
+				continue
+			}
+			if t := enclosureTypeByURL(enc.URL); t != "" {
+				enc.Type = t
+				enc.TypeInferred = true
+			}
+		}
+	}
+}
+
+// enclosureTypeByURL guesses the media type of the file at
+// rawURL from its extension. It returns an empty string when
+// the extension is missing or unknown.
+func enclosureTypeByURL(rawURL string) string {
+	p := rawURL
+	if u, err := url.Parse(strings.TrimSpace(rawURL)); err == nil {
+		p = u.Path
+	}
+	ext := strings.ToLower(path.Ext(p))
+	if ext == "" {
+		return ""
+	}
+	if t, ok := enclosureTypes[ext]; ok {
+		return t
+	}
+	// Drop parameters such as charset, enclosures are
+	// routed by the bare media type.
+	t, _, _ := strings.Cut(mime.TypeByExtension(ext), ";")
+	return strings.TrimSpace(t)
+}
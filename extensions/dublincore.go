@@ -1,5 +1,10 @@
 package ext
 
+import (
+	"strings"
+	"time"
+)
+
 // DublinCoreExtension represents a feed extension
 // for the Dublin Core specification.
 type DublinCoreExtension struct {
@@ -43,3 +48,50 @@ func NewDublinCoreExtension(extensions map[string][]Extension) *DublinCoreExtens
 	dc.Rights = parseTextArrayExtension("rights", extensions)
 	return dc
 }
+
+// DublinCoreTermsExtension represents the date bearing
+// elements of the DCMI Metadata Terms ("dcterms") namespace.
+// Each date is kept as text along with its parsed value,
+// which is nil when the text isn't a W3C-DTF date.
+type DublinCoreTermsExtension struct {
+	Created        string     `json:"created,omitempty"`
+	CreatedParsed  *time.Time `json:"createdParsed,omitempty"`
+	Modified       string     `json:"modified,omitempty"`
+	ModifiedParsed *time.Time `json:"modifiedParsed,omitempty"`
+	Issued         string     `json:"issued,omitempty"`
+	IssuedParsed   *time.Time `json:"issuedParsed,omitempty"`
+	Valid          string     `json:"valid,omitempty"`
+	ValidParsed    *time.Time `json:"validParsed,omitempty"`
+}
+
+// NewDublinCoreTermsExtension creates a new DublinCoreTermsExtension
+// given the generic extension map for the "dcterms" prefix.
+func NewDublinCoreTermsExtension(extensions map[string][]Extension) *DublinCoreTermsExtension {
+	dc := &DublinCoreTermsExtension{}
+	dc.Created, dc.CreatedParsed = parseDateExtension("created", extensions)
+	dc.Modified, dc.ModifiedParsed = parseDateExtension("modified", extensions)
+	dc.Issued, dc.IssuedParsed = parseDateExtension("issued", extensions)
+	dc.Valid, dc.ValidParsed = parseDateExtension("valid", extensions)
+	return dc
+}
+
+// Layouts of the W3C-DTF profile of ISO 8601 that DCMI
+// recommends for encoding dates, from most to least precise.
+var w3cdtfLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+func parseDateExtension(name string, extensions map[string][]Extension) (value string, parsed *time.Time) {
+	value = strings.TrimSpace(parseTextExtension(name, extensions))
+	for _, layout := range w3cdtfLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			t = t.UTC()
+			return value, &t
+		}
+	}
+	return value, nil
+}
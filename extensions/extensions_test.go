@@ -8,8 +8,10 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -46,6 +48,61 @@ func TestITunes_Extensions(t *testing.T) {
 }
 
 
+func TestITunesImage(t *testing.T) {
+	feed := &ext.ITunesFeedExtension{Image: "http://example.org/podcast.jpg"}
+	item := &ext.ITunesItemExtension{Image: "http://example.org/episode.jpg"}
+
+	assert.Equal(t, "http://example.org/episode.jpg", ext.ITunesImage(feed, item))
+	assert.Equal(t, "http://example.org/podcast.jpg", ext.ITunesImage(feed, &ext.ITunesItemExtension{}))
+	assert.Equal(t, "http://example.org/podcast.jpg", ext.ITunesImage(feed, nil))
+	assert.Equal(t, "", ext.ITunesImage(nil, nil))
+}
+
+func TestITunesFeedExtension_CategoryPaths(t *testing.T) {
+	feed := &ext.ITunesFeedExtension{Categories: []*ext.ITunesCategory{
+		{Text: "Technology", Subcategories: []*ext.ITunesCategory{{Text: "Software How-To"}, {Text: "Gadgets"}}},
+		{Text: "Society & Culture", Subcategories: []*ext.ITunesCategory{
+			{Text: "Documentary", Subcategories: []*ext.ITunesCategory{{Text: "History"}}},
+		}},
+		{Text: "Comedy"},
+		{},
+	}}
+
+	assert.Equal(t, []string{
+		"Technology/Software How-To",
+		"Technology/Gadgets",
+		"Society & Culture/Documentary/History",
+		"Comedy",
+	}, feed.CategoryPaths())
+	assert.Empty(t, (&ext.ITunesFeedExtension{}).CategoryPaths())
+}
+
+func TestITunesItemExtension_DurationParsed(t *testing.T) {
+	var durationTests = []struct {
+		duration string
+		expected time.Duration
+	}{
+		{"742", 742 * time.Second},
+		{"03:12", 3*time.Minute + 12*time.Second},
+		{"1:02:03", time.Hour + 2*time.Minute + 3*time.Second},
+		{" 90:00 ", 90 * time.Minute},
+		{"12.5", 12500 * time.Millisecond},
+		{"", 0},
+		{"about an hour", 0},
+		{"1:xx", 0},
+		{"-5", 0},
+		{"1:2:3:4", 0},
+	}
+
+	for _, test := range durationTests {
+		item := ext.NewITunesItemExtension(map[string][]ext.Extension{
+			"duration": {{Name: "duration", Value: test.duration}},
+		})
+		assert.Equal(t, test.duration, item.Duration)
+		assert.Equal(t, test.expected, item.DurationParsed, test.duration)
+	}
+}
+
 func TestMedia_Extensions(t *testing.T) {
 	files, _ := filepath.Glob("../testdata/extensions/media/*.xml")
 	for _, f := range files {
@@ -77,3 +134,99 @@ func TestMedia_Extensions(t *testing.T) {
 		}
 	}
 }
+
+func TestDublinCoreTerms_Extensions(t *testing.T) {
+	files, _ := filepath.Glob("../testdata/extensions/dcterms/*.xml")
+	for _, f := range files {
+		base := filepath.Base(f)
+		name := strings.TrimSuffix(base, filepath.Ext(base))
+
+		fmt.Printf("Testing %s... ", name)
+
+		// Get actual source feed
+		ff := fmt.Sprintf("../testdata/extensions/dcterms/%s.xml", name)
+		f, _ := os.ReadFile(ff)
+
+		// Parse actual feed
+		fp := gofeed.NewParser()
+		actual, _ := fp.Parse(bytes.NewReader(f))
+
+		// Get json encoded expected feed result
+		ef := fmt.Sprintf("../testdata/extensions/dcterms/%s.json", name)
+		e, _ := os.ReadFile(ef)
+
+		// Unmarshal expected feed
+		expected := &gofeed.Feed{}
+		json.Unmarshal(e, &expected)
+
+		if assert.Equal(t, expected, actual, "Feed file %s.xml did not match expected output %s.json", name, name) {
+			fmt.Printf("OK\n")
+		} else {
+			fmt.Printf("Failed\n")
+		}
+	}
+}
+
+func TestGeoRSS_Extensions(t *testing.T) {
+	files, _ := filepath.Glob("../testdata/extensions/georss/*.xml")
+	for _, f := range files {
+		base := filepath.Base(f)
+		name := strings.TrimSuffix(base, filepath.Ext(base))
+
+		fmt.Printf("Testing %s... ", name)
+
+		// Get actual source feed
+		ff := fmt.Sprintf("../testdata/extensions/georss/%s.xml", name)
+		f, _ := os.ReadFile(ff)
+
+		// Parse actual feed
+		fp := gofeed.NewParser()
+		actual, _ := fp.Parse(bytes.NewReader(f))
+
+		// Get json encoded expected feed result
+		ef := fmt.Sprintf("../testdata/extensions/georss/%s.json", name)
+		e, _ := os.ReadFile(ef)
+
+		// Unmarshal expected feed
+		expected := &gofeed.Feed{}
+		json.Unmarshal(e, &expected)
+
+		if assert.Equal(t, expected, actual, "Feed file %s.xml did not match expected output %s.json", name, name) {
+			fmt.Printf("OK\n")
+		} else {
+			fmt.Printf("Failed\n")
+		}
+	}
+}
+
+func TestXCal_Extensions(t *testing.T) {
+	files, _ := filepath.Glob("../testdata/extensions/xcal/*.xml")
+	for _, f := range files {
+		base := filepath.Base(f)
+		name := strings.TrimSuffix(base, filepath.Ext(base))
+
+		fmt.Printf("Testing %s... ", name)
+
+		// Get actual source feed
+		ff := fmt.Sprintf("../testdata/extensions/xcal/%s.xml", name)
+		f, _ := os.ReadFile(ff)
+
+		// Parse actual feed
+		fp := gofeed.NewParser()
+		actual, _ := fp.Parse(bytes.NewReader(f))
+
+		// Get json encoded expected feed result
+		ef := fmt.Sprintf("../testdata/extensions/xcal/%s.json", name)
+		e, _ := os.ReadFile(ef)
+
+		// Unmarshal expected feed
+		expected := &gofeed.Feed{}
+		json.Unmarshal(e, &expected)
+
+		if assert.Equal(t, expected, actual, "Feed file %s.xml did not match expected output %s.json", name, name) {
+			fmt.Printf("OK\n")
+		} else {
+			fmt.Printf("Failed\n")
+		}
+	}
+}
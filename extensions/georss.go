@@ -0,0 +1,69 @@
+package ext
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// GeoExtension is a set of extension fields for the
+// GeoRSS Simple encoding.
+// https://www.ogc.org/standard/georss/
+type GeoExtension struct {
+	Point       *GeoPoint   `json:"point,omitempty"`
+	Line        []*GeoPoint `json:"line,omitempty"`
+	Polygon     []*GeoPoint `json:"polygon,omitempty"`
+	Box         []*GeoPoint `json:"box,omitempty"` // Lower corner, then upper corner
+	FeatureName string      `json:"featureName,omitempty"`
+	Elevation   float64     `json:"elevation,omitempty"` // Meters
+}
+
+// GeoPoint is a WGS84 latitude/longitude pair.
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// NewGeoExtension creates a GeoExtension given an
+// extension map for the "georss" key. Geometries whose
+// coordinates can't be parsed are left out.
+func NewGeoExtension(extensions map[string][]Extension) *GeoExtension {
+	geo := &GeoExtension{}
+	if points := parseGeoPoints("point", extensions); len(points) == 1 {
+		geo.Point = points[0]
+	}
+	if points := parseGeoPoints("line", extensions); len(points) >= 2 {
+		geo.Line = points
+	}
+	if points := parseGeoPoints("polygon", extensions); len(points) >= 4 {
+		geo.Polygon = points
+	}
+	if points := parseGeoPoints("box", extensions); len(points) == 2 {
+		geo.Box = points
+	}
+	geo.FeatureName = strings.TrimSpace(parseTextExtension("featureName", extensions))
+	geo.Elevation = parseFloatAttr(parseTextExtension("elev", extensions))
+	return geo
+}
+
+// parseGeoPoints parses the whitespace separated list of
+// latitudes and longitudes of a GeoRSS geometry.
+func parseGeoPoints(name string, extensions map[string][]Extension) (points []*GeoPoint) {
+	fields := strings.Fields(parseTextExtension(name, extensions))
+	if len(fields) == 0 || len(fields)%2 != 0 {
+		return nil
+	}
+
+	for i := 0; i < len(fields); i += 2 {
+		lat, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil || math.Abs(lat) > 90 {
+			return nil
+		}
+		lon, err := strconv.ParseFloat(fields[i+1], 64)
+		if err != nil || math.Abs(lon) > 180 {
+			return nil
+		}
+		points = append(points, &GeoPoint{Lat: lat, Lon: lon})
+	}
+	return
+}
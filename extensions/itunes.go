@@ -1,44 +1,82 @@
 package ext
 
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // ITunesFeedExtension is a set of extension
 // fields for RSS feeds.
 type ITunesFeedExtension struct {
-	Author     string            `json:"author,omitempty"`
-	Block      string            `json:"block,omitempty"`
-	Categories []*ITunesCategory `json:"categories,omitempty"`
-	Explicit   string            `json:"explicit,omitempty"`
-	Keywords   string            `json:"keywords,omitempty"`
-	Owner      *ITunesOwner      `json:"owner,omitempty"`
-	Subtitle   string            `json:"subtitle,omitempty"`
-	Summary    string            `json:"summary,omitempty"`
-	Image      string            `json:"image,omitempty"`
-	Complete   string            `json:"complete,omitempty"`
-	NewFeedURL string            `json:"newFeedUrl,omitempty"`
-	Type       string            `json:"type,omitempty"`
+	Author      string            `json:"author,omitempty"`
+	Block       string            `json:"block,omitempty"`
+	Categories  []*ITunesCategory `json:"categories,omitempty"`
+	Explicit    string            `json:"explicit,omitempty"`
+	Keywords    string            `json:"keywords,omitempty"`
+	KeywordList []string          `json:"keywordList,omitempty"`
+	Owner       *ITunesOwner      `json:"owner,omitempty"`
+	Subtitle    string            `json:"subtitle,omitempty"`
+	Summary     string            `json:"summary,omitempty"`
+	Image       string            `json:"image,omitempty"`
+	Complete    string            `json:"complete,omitempty"`
+	NewFeedURL  string            `json:"newFeedUrl,omitempty"`
+	Type        string            `json:"type,omitempty"`
 }
 
 // ITunesItemExtension is a set of extension
 // fields for RSS items.
 type ITunesItemExtension struct {
-	Author            string `json:"author,omitempty"`
-	Block             string `json:"block,omitempty"`
-	Duration          string `json:"duration,omitempty"`
-	Explicit          string `json:"explicit,omitempty"`
-	Keywords          string `json:"keywords,omitempty"`
-	Subtitle          string `json:"subtitle,omitempty"`
-	Summary           string `json:"summary,omitempty"`
-	Image             string `json:"image,omitempty"`
-	IsClosedCaptioned string `json:"isClosedCaptioned,omitempty"`
-	Episode           string `json:"episode,omitempty"`
-	Season            string `json:"season,omitempty"`
-	Order             string `json:"order,omitempty"`
-	EpisodeType       string `json:"episodeType,omitempty"`
+	Author            string        `json:"author,omitempty"`
+	Block             string        `json:"block,omitempty"`
+	Duration          string        `json:"duration,omitempty"`
+	DurationParsed    time.Duration `json:"durationParsed,omitempty"`
+	Explicit          string        `json:"explicit,omitempty"`
+	Keywords          string        `json:"keywords,omitempty"`
+	KeywordList       []string      `json:"keywordList,omitempty"`
+	Subtitle          string        `json:"subtitle,omitempty"`
+	Summary           string        `json:"summary,omitempty"`
+	Image             string        `json:"image,omitempty"`
+	IsClosedCaptioned string        `json:"isClosedCaptioned,omitempty"`
+	Episode           string        `json:"episode,omitempty"`
+	Season            string        `json:"season,omitempty"`
+	Order             string        `json:"order,omitempty"`
+	EpisodeType       string        `json:"episodeType,omitempty"`
 }
 
 // ITunesCategory is a category element for itunes feeds.
+// Subcategories holds its nested category elements, and
+// Subcategory the first of them.
 type ITunesCategory struct {
-	Text        string          `json:"text,omitempty"`
-	Subcategory *ITunesCategory `json:"subcategory,omitempty"`
+	Text          string            `json:"text,omitempty"`
+	Subcategory   *ITunesCategory   `json:"subcategory,omitempty"`
+	Subcategories []*ITunesCategory `json:"subcategories,omitempty"`
+}
+
+// CategoryPaths flattens the category tree of the feed into
+// slash separated paths such as "Technology/Software How-To",
+// one for every leaf category.
+func (e *ITunesFeedExtension) CategoryPaths() (paths []string) {
+	for _, c := range e.Categories {
+		paths = append(paths, c.paths()...)
+	}
+	return
+}
+
+func (c *ITunesCategory) paths() (paths []string) {
+	if c.Text == "" {
+		return nil
+	}
+	for _, s := range c.Subcategories {
+		for _, p := range s.paths() {
+			paths = append(paths, c.Text+"/"+p)
+		}
+	}
+	if len(paths) == 0 {
+		paths = []string{c.Text}
+	}
+	return
 }
 
 // ITunesOwner is the owner of a particular itunes feed.
@@ -55,6 +93,7 @@ func NewITunesFeedExtension(extensions map[string][]Extension) *ITunesFeedExtens
 	feed.Block = parseTextExtension("block", extensions)
 	feed.Explicit = parseTextExtension("explicit", extensions)
 	feed.Keywords = parseTextExtension("keywords", extensions)
+	feed.KeywordList = splitKeywords(feed.Keywords)
 	feed.Subtitle = parseTextExtension("subtitle", extensions)
 	feed.Summary = parseTextExtension("summary", extensions)
 	feed.Image = parseImage(extensions)
@@ -73,10 +112,12 @@ func NewITunesItemExtension(extensions map[string][]Extension) *ITunesItemExtens
 	entry.Author = parseTextExtension("author", extensions)
 	entry.Block = parseTextExtension("block", extensions)
 	entry.Duration = parseTextExtension("duration", extensions)
+	entry.DurationParsed = parseDuration(entry.Duration)
 	entry.Explicit = parseTextExtension("explicit", extensions)
 	entry.Subtitle = parseTextExtension("subtitle", extensions)
 	entry.Summary = parseTextExtension("summary", extensions)
 	entry.Keywords = parseTextExtension("keywords", extensions)
+	entry.KeywordList = splitKeywords(entry.Keywords)
 	entry.Image = parseImage(extensions)
 	entry.IsClosedCaptioned = parseTextExtension("isClosedCaptioned", extensions)
 	entry.Episode = parseTextExtension("episode", extensions)
@@ -86,6 +127,56 @@ func NewITunesItemExtension(extensions map[string][]Extension) *ITunesItemExtens
 	return entry
 }
 
+// ITunesImage returns the artwork to display for an item: the
+// item's own itunes:image if it has one, otherwise the one of
+// the feed. Either extension may be nil.
+func ITunesImage(feed *ITunesFeedExtension, item *ITunesItemExtension) string {
+	if item != nil && item.Image != "" {
+		return item.Image
+	}
+	if feed != nil {
+		return feed.Image
+	}
+	return ""
+}
+
+// ResolveITunes returns the effective itunes fields of an
+// item: a copy of item with the author, image, explicit and
+// block values it leaves empty inherited from feed, as podcast
+// clients do. item itself is left untouched so the values the
+// episode declares can still be told apart. Either extension
+// may be nil; it returns nil when both are.
+func ResolveITunes(feed *ITunesFeedExtension, item *ITunesItemExtension) *ITunesItemExtension {
+	if feed == nil && item == nil {
+		return nil
+	}
+
+	resolved := &ITunesItemExtension{}
+	if item != nil {
+		*resolved = *item
+	}
+	if feed == nil {
+		return resolved
+	}
+
+	if resolved.Author == "" {
+		resolved.Author = feed.Author
+	}
+	if resolved.Image == "" {
+		resolved.Image = feed.Image
+	}
+	if resolved.Explicit == "" {
+		resolved.Explicit = feed.Explicit
+	}
+	if resolved.Block == "" {
+		resolved.Block = feed.Block
+	}
+	return resolved
+}
+
+// parseImage reads the href attribute of the (normally self
+// closing) itunes:image element, falling back to its text for
+// feeds that put the URL in the element body instead.
 func parseImage(extensions map[string][]Extension) (image string) {
 	if extensions == nil {
 		return
@@ -96,7 +187,10 @@ func parseImage(extensions map[string][]Extension) (image string) {
 		return
 	}
 
-	image = matches[0].Attrs["href"]
+	image = strings.TrimSpace(matches[0].Attrs["href"])
+	if image == "" {
+		image = strings.TrimSpace(matches[0].Value)
+	}
 	return
 }
 
@@ -132,19 +226,69 @@ func parseCategories(extensions map[string][]Extension) (categories []*ITunesCat
 
 	categories = []*ITunesCategory{}
 	for _, cat := range matches {
-		c := &ITunesCategory{}
-		if text, ok := cat.Attrs["text"]; ok {
-			c.Text = text
-		}
+		categories = append(categories, parseCategory(cat))
+	}
+	return
+}
+
+func parseCategory(cat Extension) *ITunesCategory {
+	c := &ITunesCategory{}
+	if text, ok := cat.Attrs["text"]; ok {
+		c.Text = text
+	}
 
-		if subs, ok := cat.Children["category"]; ok {
-			s := &ITunesCategory{}
-			if text, ok := subs[0].Attrs["text"]; ok {
-				s.Text = text
-			}
-			c.Subcategory = s
+	for _, sub := range cat.Children["category"] {
+		c.Subcategories = append(c.Subcategories, parseCategory(sub))
+	}
+	if len(c.Subcategories) > 0 {
+		c.Subcategory = c.Subcategories[0]
+	}
+	return c
+}
+
+// splitKeywords splits an itunes:keywords list on commas, or
+// on whitespace when there are no commas, dropping empty
+// keywords.
+func splitKeywords(keywords string) (list []string) {
+	var fields []string
+	if strings.Contains(keywords, ",") {
+		fields = strings.Split(keywords, ",")
+	} else {
+		fields = strings.Fields(keywords)
+	}
+	for _, f := range fields {
+		if f = strings.TrimSpace(f); f != "" {
+			list = append(list, f)
 		}
-		categories = append(categories, c)
 	}
 	return
 }
+
+// parseDuration parses an itunes:duration given in seconds
+// or as MM:SS or HH:MM:SS. It returns zero if the duration
+// is in none of these forms.
+func parseDuration(duration string) time.Duration {
+	parts := strings.Split(strings.TrimSpace(duration), ":")
+	if len(parts) > 3 {
+		return 0
+	}
+
+	// The seconds may have a fraction, the
+	// hours and minutes must be whole numbers.
+	seconds, err := strconv.ParseFloat(parts[len(parts)-1], 64)
+	if err != nil || seconds < 0 || math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+		return 0
+	}
+	total := time.Duration(seconds * float64(time.Second))
+
+	unit := time.Minute
+	for i := len(parts) - 2; i >= 0; i-- {
+		n, err := strconv.ParseUint(parts[i], 10, 32)
+		if err != nil {
+			return 0
+		}
+		total += time.Duration(n) * unit
+		unit *= 60
+	}
+	return total
+}
@@ -0,0 +1,508 @@
+package ext
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MediaExtension is a set of extension fields
+// for the Media RSS specification.
+// https://www.rssboard.org/media-rss
+type MediaExtension struct {
+	Title           string              `json:"title,omitempty"`
+	TitleType       string              `json:"titleType,omitempty"`
+	Description     string              `json:"description,omitempty"`
+	DescriptionType string              `json:"descriptionType,omitempty"`
+	Thumbnails      []*MediaThumbnail   `json:"thumbnails,omitempty"`
+	Credits         []*MediaCredit      `json:"credits,omitempty"`
+	Ratings         []*MediaRating      `json:"ratings,omitempty"`
+	Restrictions    []*MediaRestriction `json:"restrictions,omitempty"`
+	Player          *MediaPlayer        `json:"player,omitempty"`
+	Embed           *MediaEmbed         `json:"embed,omitempty"`
+	Community       *MediaCommunity     `json:"community,omitempty"`
+	Contents        []*MediaContent     `json:"contents,omitempty"`
+	Groups          []*MediaGroup       `json:"groups,omitempty"`
+}
+
+// MediaGroup is a media:group element which groups several
+// representations of the same media. Metadata declared on the
+// group has already been inherited by each of its Contents.
+//
+// TitleType and DescriptionType tell whether the media:title
+// and media:description are "plain" text or "html", and are
+// only set when the element is present. The same goes for
+// MediaExtension and MediaContent.
+type MediaGroup struct {
+	Title           string              `json:"title,omitempty"`
+	TitleType       string              `json:"titleType,omitempty"`
+	Description     string              `json:"description,omitempty"`
+	DescriptionType string              `json:"descriptionType,omitempty"`
+	Thumbnails      []*MediaThumbnail   `json:"thumbnails,omitempty"`
+	Credits         []*MediaCredit      `json:"credits,omitempty"`
+	Ratings         []*MediaRating      `json:"ratings,omitempty"`
+	Restrictions    []*MediaRestriction `json:"restrictions,omitempty"`
+	Player          *MediaPlayer        `json:"player,omitempty"`
+	Embed           *MediaEmbed         `json:"embed,omitempty"`
+	Community       *MediaCommunity     `json:"community,omitempty"`
+	Contents        []*MediaContent     `json:"contents,omitempty"`
+}
+
+// MediaContent is a media:content element describing a
+// single media object. The numeric attributes are also
+// available parsed, as zero when missing or malformed.
+type MediaContent struct {
+	URL             string              `json:"url,omitempty"`
+	FileSize        string              `json:"fileSize,omitempty"`
+	FileSizeInt     int64               `json:"fileSizeInt,omitempty"`
+	Type            string              `json:"type,omitempty"`
+	Medium          string              `json:"medium,omitempty"`
+	IsDefault       string              `json:"isDefault,omitempty"`
+	Expression      string              `json:"expression,omitempty"`
+	Bitrate         string              `json:"bitrate,omitempty"`
+	BitrateFloat    float64             `json:"bitrateFloat,omitempty"` // Kilobits per second
+	Framerate       string              `json:"framerate,omitempty"`
+	FramerateFloat  float64             `json:"framerateFloat,omitempty"`
+	SamplingRate    string              `json:"samplingRate,omitempty"`
+	Channels        string              `json:"channels,omitempty"`
+	Duration        string              `json:"duration,omitempty"`
+	DurationInt     int                 `json:"durationInt,omitempty"` // Seconds
+	Height          string              `json:"height,omitempty"`
+	HeightInt       int                 `json:"heightInt,omitempty"`
+	Width           string              `json:"width,omitempty"`
+	WidthInt        int                 `json:"widthInt,omitempty"`
+	Lang            string              `json:"lang,omitempty"`
+	Title           string              `json:"title,omitempty"`
+	TitleType       string              `json:"titleType,omitempty"`
+	Description     string              `json:"description,omitempty"`
+	DescriptionType string              `json:"descriptionType,omitempty"`
+	Thumbnails      []*MediaThumbnail   `json:"thumbnails,omitempty"`
+	Credits         []*MediaCredit      `json:"credits,omitempty"`
+	Ratings         []*MediaRating      `json:"ratings,omitempty"`
+	Restrictions    []*MediaRestriction `json:"restrictions,omitempty"`
+	Player          *MediaPlayer        `json:"player,omitempty"`
+	Embed           *MediaEmbed         `json:"embed,omitempty"`
+	Community       *MediaCommunity     `json:"community,omitempty"`
+}
+
+// MediaThumbnail is a media:thumbnail element.
+type MediaThumbnail struct {
+	URL    string `json:"url,omitempty"`
+	Width  string `json:"width,omitempty"`
+	Height string `json:"height,omitempty"`
+	Time   string `json:"time,omitempty"`
+}
+
+// MediaRestriction is a media:restriction element limiting
+// where the media may be shown. Type is "country", "uri" or
+// "sharing" and Relationship "allow" or "deny". Values holds
+// the whitespace separated entries of Value, e.g. the ISO 3166
+// country codes; "all" and "none" are kept as they are.
+type MediaRestriction struct {
+	Type         string   `json:"type,omitempty"`
+	Relationship string   `json:"relationship,omitempty"`
+	Value        string   `json:"value,omitempty"`
+	Values       []string `json:"values,omitempty"`
+}
+
+// MediaPlayer is a media:player element, the url of a web
+// page that plays the media, e.g. in an iframe.
+type MediaPlayer struct {
+	URL    string `json:"url,omitempty"`
+	Width  string `json:"width,omitempty"`
+	Height string `json:"height,omitempty"`
+}
+
+// MediaEmbed is a media:embed element describing an embeddable
+// player. Params holds its media:param children by name.
+type MediaEmbed struct {
+	URL    string            `json:"url,omitempty"`
+	Width  string            `json:"width,omitempty"`
+	Height string            `json:"height,omitempty"`
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// MediaCommunity is a media:community element with the
+// ratings, statistics and tags users gave the media.
+type MediaCommunity struct {
+	StarRating *MediaStarRating `json:"starRating,omitempty"`
+	Statistics *MediaStatistics `json:"statistics,omitempty"`
+	Tags       []*MediaTag      `json:"tags,omitempty"`
+}
+
+// MediaStarRating is a media:starRating element. Fields that
+// are missing or not numbers are left zero.
+type MediaStarRating struct {
+	Average float64 `json:"average,omitempty"`
+	Count   int     `json:"count,omitempty"`
+	Min     int     `json:"min,omitempty"`
+	Max     int     `json:"max,omitempty"`
+}
+
+// MediaStatistics is a media:statistics element. Fields that
+// are missing or not numbers are left zero.
+type MediaStatistics struct {
+	Views     int64 `json:"views,omitempty"`
+	Favorites int64 `json:"favorites,omitempty"`
+}
+
+// MediaTag is one of the comma separated entries of a
+// media:tags element. Weight defaults to 1.
+type MediaTag struct {
+	Name   string `json:"name,omitempty"`
+	Weight int    `json:"weight,omitempty"`
+}
+
+// MediaCredit is a media:credit element naming an entity
+// that contributed to the media.
+type MediaCredit struct {
+	Role   string `json:"role,omitempty"`
+	Scheme string `json:"scheme,omitempty"`
+	Value  string `json:"value,omitempty"`
+}
+
+// MediaRating is a media:rating element. Scheme defaults to
+// "urn:simple", whose values are "adult" and "nonadult".
+type MediaRating struct {
+	Scheme string `json:"scheme,omitempty"`
+	Value  string `json:"value,omitempty"`
+}
+
+// NewMediaExtension creates a MediaExtension given an
+// extension map for the "media" key.
+func NewMediaExtension(extensions map[string][]Extension) *MediaExtension {
+	media := &MediaExtension{}
+	media.Title, media.TitleType = parseMediaText("title", extensions)
+	media.Description, media.DescriptionType = parseMediaText("description", extensions)
+	media.Thumbnails = parseMediaThumbnails(extensions)
+	media.Credits = parseMediaCredits(extensions)
+	media.Ratings = parseMediaRatings(extensions)
+	media.Restrictions = parseMediaRestrictions(extensions)
+	media.Player = parseMediaPlayer(extensions)
+	media.Embed = parseMediaEmbed(extensions)
+	media.Community = parseMediaCommunity(extensions)
+	media.Contents = parseMediaContents(extensions)
+	media.Groups = parseMediaGroups(extensions)
+	return media
+}
+
+func parseMediaGroups(extensions map[string][]Extension) (groups []*MediaGroup) {
+	if extensions == nil {
+		return
+	}
+
+	matches, ok := extensions["group"]
+	if !ok || len(matches) == 0 {
+		return
+	}
+
+	groups = []*MediaGroup{}
+	for _, m := range matches {
+		g := &MediaGroup{}
+		g.Title, g.TitleType = parseMediaText("title", m.Children)
+		g.Description, g.DescriptionType = parseMediaText("description", m.Children)
+		g.Thumbnails = parseMediaThumbnails(m.Children)
+		g.Credits = parseMediaCredits(m.Children)
+		g.Ratings = parseMediaRatings(m.Children)
+		g.Restrictions = parseMediaRestrictions(m.Children)
+		g.Player = parseMediaPlayer(m.Children)
+		g.Embed = parseMediaEmbed(m.Children)
+		g.Community = parseMediaCommunity(m.Children)
+		g.Contents = parseMediaContents(m.Children)
+
+		// Group level metadata applies to every content
+		// element that doesn't override it.
+		for _, c := range g.Contents {
+			if c.Title == "" {
+				c.Title, c.TitleType = g.Title, g.TitleType
+			}
+			if c.Description == "" {
+				c.Description, c.DescriptionType = g.Description, g.DescriptionType
+			}
+			if c.Thumbnails == nil {
+				c.Thumbnails = g.Thumbnails
+			}
+			if c.Credits == nil {
+				c.Credits = g.Credits
+			}
+			if c.Ratings == nil {
+				c.Ratings = g.Ratings
+			}
+			if c.Restrictions == nil {
+				c.Restrictions = g.Restrictions
+			}
+			if c.Player == nil {
+				c.Player = g.Player
+			}
+			if c.Embed == nil {
+				c.Embed = g.Embed
+			}
+			if c.Community == nil {
+				c.Community = g.Community
+			}
+		}
+		groups = append(groups, g)
+	}
+	return
+}
+
+func parseMediaContents(extensions map[string][]Extension) (contents []*MediaContent) {
+	if extensions == nil {
+		return
+	}
+
+	matches, ok := extensions["content"]
+	if !ok || len(matches) == 0 {
+		return
+	}
+
+	contents = []*MediaContent{}
+	for _, m := range matches {
+		c := &MediaContent{}
+		c.URL = m.Attrs["url"]
+		c.FileSize = m.Attrs["fileSize"]
+		c.Type = m.Attrs["type"]
+		c.Medium = m.Attrs["medium"]
+		c.IsDefault = m.Attrs["isDefault"]
+		c.Expression = m.Attrs["expression"]
+		c.Bitrate = m.Attrs["bitrate"]
+		c.Framerate = m.Attrs["framerate"]
+		c.SamplingRate = m.Attrs["samplingrate"]
+		c.Channels = m.Attrs["channels"]
+		c.Duration = m.Attrs["duration"]
+		c.Height = m.Attrs["height"]
+		c.Width = m.Attrs["width"]
+		c.FileSizeInt = parseIntAttr(c.FileSize)
+		c.BitrateFloat = parseFloatAttr(c.Bitrate)
+		c.FramerateFloat = parseFloatAttr(c.Framerate)
+		c.DurationInt = int(parseFloatAttr(c.Duration))
+		c.HeightInt = int(parseIntAttr(c.Height))
+		c.WidthInt = int(parseIntAttr(c.Width))
+		c.Lang = m.Attrs["lang"]
+		c.Title, c.TitleType = parseMediaText("title", m.Children)
+		c.Description, c.DescriptionType = parseMediaText("description", m.Children)
+		c.Thumbnails = parseMediaThumbnails(m.Children)
+		c.Credits = parseMediaCredits(m.Children)
+		c.Ratings = parseMediaRatings(m.Children)
+		c.Restrictions = parseMediaRestrictions(m.Children)
+		c.Player = parseMediaPlayer(m.Children)
+		c.Embed = parseMediaEmbed(m.Children)
+		c.Community = parseMediaCommunity(m.Children)
+		contents = append(contents, c)
+	}
+	return
+}
+
+// parseIntAttr returns the integer value of an
+// attribute, or zero if it isn't a valid integer.
+func parseIntAttr(value string) int64 {
+	i, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return i
+}
+
+// parseFloatAttr returns the numeric value of an
+// attribute, or zero if it isn't a valid number.
+func parseFloatAttr(value string) float64 {
+	f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0
+	}
+	return f
+}
+
+// parseMediaText returns the value of the first media:title
+// or media:description element along with its type, which
+// defaults to "plain".
+func parseMediaText(name string, extensions map[string][]Extension) (value, textType string) {
+	matches, ok := extensions[name]
+	if !ok || len(matches) == 0 {
+		return
+	}
+
+	textType = strings.ToLower(strings.TrimSpace(matches[0].Attrs["type"]))
+	if textType == "" {
+		textType = "plain"
+	}
+	return matches[0].Value, textType
+}
+
+func parseMediaThumbnails(extensions map[string][]Extension) (thumbnails []*MediaThumbnail) {
+	if extensions == nil {
+		return
+	}
+
+	matches, ok := extensions["thumbnail"]
+	if !ok || len(matches) == 0 {
+		return
+	}
+
+	thumbnails = []*MediaThumbnail{}
+	for _, m := range matches {
+		t := &MediaThumbnail{}
+		t.URL = m.Attrs["url"]
+		t.Width = m.Attrs["width"]
+		t.Height = m.Attrs["height"]
+		t.Time = m.Attrs["time"]
+		thumbnails = append(thumbnails, t)
+	}
+	return
+}
+
+func parseMediaCredits(extensions map[string][]Extension) (credits []*MediaCredit) {
+	if extensions == nil {
+		return
+	}
+
+	matches, ok := extensions["credit"]
+	if !ok || len(matches) == 0 {
+		return
+	}
+
+	credits = []*MediaCredit{}
+	for _, m := range matches {
+		c := &MediaCredit{}
+		c.Role = m.Attrs["role"]
+		c.Scheme = m.Attrs["scheme"]
+		c.Value = m.Value
+		credits = append(credits, c)
+	}
+	return
+}
+
+// parseMediaPlayer returns the first media:player element,
+// or nil if there is none.
+func parseMediaPlayer(extensions map[string][]Extension) *MediaPlayer {
+	matches, ok := extensions["player"]
+	if !ok || len(matches) == 0 {
+		return nil
+	}
+
+	m := matches[0]
+	return &MediaPlayer{
+		URL:    strings.TrimSpace(m.Attrs["url"]),
+		Width:  m.Attrs["width"],
+		Height: m.Attrs["height"],
+	}
+}
+
+// parseMediaEmbed returns the first media:embed element,
+// or nil if there is none.
+func parseMediaEmbed(extensions map[string][]Extension) *MediaEmbed {
+	matches, ok := extensions["embed"]
+	if !ok || len(matches) == 0 {
+		return nil
+	}
+
+	m := matches[0]
+	embed := &MediaEmbed{
+		URL:    strings.TrimSpace(m.Attrs["url"]),
+		Width:  m.Attrs["width"],
+		Height: m.Attrs["height"],
+	}
+	for _, param := range m.Children["param"] {
+		if name := param.Attrs["name"]; name != "" {
+			if embed.Params == nil {
+				embed.Params = map[string]string{}
+			}
+			embed.Params[name] = strings.TrimSpace(param.Value)
+		}
+	}
+	return embed
+}
+
+// parseMediaCommunity returns the first media:community
+// element, or nil if there is none.
+func parseMediaCommunity(extensions map[string][]Extension) *MediaCommunity {
+	matches, ok := extensions["community"]
+	if !ok || len(matches) == 0 {
+		return nil
+	}
+
+	m := matches[0]
+	community := &MediaCommunity{}
+	if ratings := m.Children["starRating"]; len(ratings) > 0 {
+		r := ratings[0]
+		community.StarRating = &MediaStarRating{
+			Average: parseFloatAttr(r.Attrs["average"]),
+			Count:   int(parseIntAttr(r.Attrs["count"])),
+			Min:     int(parseIntAttr(r.Attrs["min"])),
+			Max:     int(parseIntAttr(r.Attrs["max"])),
+		}
+	}
+	if statistics := m.Children["statistics"]; len(statistics) > 0 {
+		st := statistics[0]
+		community.Statistics = &MediaStatistics{
+			Views:     parseIntAttr(st.Attrs["views"]),
+			Favorites: parseIntAttr(st.Attrs["favorites"]),
+		}
+	}
+	if tags := m.Children["tags"]; len(tags) > 0 {
+		community.Tags = parseMediaTags(tags[0].Value)
+	}
+	return community
+}
+
+// parseMediaTags splits the value of a media:tags element,
+// e.g. "news: 5, abc:3", into its tags and their weights.
+func parseMediaTags(value string) (tags []*MediaTag) {
+	for _, entry := range strings.Split(value, ",") {
+		t := &MediaTag{Name: strings.TrimSpace(entry), Weight: 1}
+		if i := strings.LastIndex(t.Name, ":"); i >= 0 {
+			if weight, err := strconv.Atoi(strings.TrimSpace(t.Name[i+1:])); err == nil {
+				t.Name, t.Weight = strings.TrimSpace(t.Name[:i]), weight
+			}
+		}
+		if t.Name != "" {
+			tags = append(tags, t)
+		}
+	}
+	return
+}
+
+func parseMediaRatings(extensions map[string][]Extension) (ratings []*MediaRating) {
+	if extensions == nil {
+		return
+	}
+
+	matches, ok := extensions["rating"]
+	if !ok || len(matches) == 0 {
+		return
+	}
+
+	ratings = []*MediaRating{}
+	for _, m := range matches {
+		r := &MediaRating{}
+		r.Scheme = m.Attrs["scheme"]
+		if r.Scheme == "" {
+			r.Scheme = "urn:simple"
+		}
+		r.Value = strings.TrimSpace(m.Value)
+		ratings = append(ratings, r)
+	}
+	return
+}
+
+func parseMediaRestrictions(extensions map[string][]Extension) (restrictions []*MediaRestriction) {
+	if extensions == nil {
+		return
+	}
+
+	matches, ok := extensions["restriction"]
+	if !ok || len(matches) == 0 {
+		return
+	}
+
+	restrictions = []*MediaRestriction{}
+	for _, m := range matches {
+		r := &MediaRestriction{}
+		r.Type = strings.ToLower(strings.TrimSpace(m.Attrs["type"]))
+		r.Relationship = strings.ToLower(strings.TrimSpace(m.Attrs["relationship"]))
+		r.Value = strings.TrimSpace(m.Value)
+		r.Values = strings.Fields(r.Value)
+		restrictions = append(restrictions, r)
+	}
+	return
+}
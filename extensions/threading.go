@@ -0,0 +1,47 @@
+package ext
+
+// ThreadingExtension is a set of extension fields
+// for the Atom Threading Extensions.
+// https://www.rfc-editor.org/rfc/rfc4685
+type ThreadingExtension struct {
+	InReplyTo []*InReplyTo `json:"inReplyTo,omitempty"`
+}
+
+// InReplyTo is a thr:in-reply-to element pointing at the
+// resource an entry is a response to.
+type InReplyTo struct {
+	Ref    string `json:"ref,omitempty"`
+	Href   string `json:"href,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Source string `json:"source,omitempty"`
+}
+
+// NewThreadingExtension creates a ThreadingExtension given an
+// extension map for the "thr" key.
+func NewThreadingExtension(extensions map[string][]Extension) *ThreadingExtension {
+	thr := &ThreadingExtension{}
+	thr.InReplyTo = parseInReplyTo(extensions)
+	return thr
+}
+
+func parseInReplyTo(extensions map[string][]Extension) (replies []*InReplyTo) {
+	if extensions == nil {
+		return
+	}
+
+	matches, ok := extensions["in-reply-to"]
+	if !ok || len(matches) == 0 {
+		return
+	}
+
+	replies = []*InReplyTo{}
+	for _, m := range matches {
+		r := &InReplyTo{}
+		r.Ref = m.Attrs["ref"]
+		r.Href = m.Attrs["href"]
+		r.Type = m.Attrs["type"]
+		r.Source = m.Attrs["source"]
+		replies = append(replies, r)
+	}
+	return
+}
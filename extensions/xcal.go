@@ -0,0 +1,122 @@
+package ext
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EventExtension is a set of extension fields for the
+// xCal representation of an iCalendar event embedded in
+// a feed item.
+// https://www.rfc-editor.org/rfc/rfc6321
+type EventExtension struct {
+	Start    *time.Time `json:"start,omitempty"`
+	End      *time.Time `json:"end,omitempty"`
+	Location string     `json:"location,omitempty"`
+	Summary  string     `json:"summary,omitempty"`
+	Geo      *GeoPoint  `json:"geo,omitempty"`
+}
+
+// Layouts of the xCal date-time and date values, along with
+// the basic iCalendar forms older feeds use. Times without a
+// zone are floating and are parsed as UTC.
+var xcalTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"20060102T150405Z",
+	"20060102T150405",
+	"20060102",
+}
+
+// Elements RFC 6321 nests the properties of an event in,
+// outermost first.
+var xcalEventPath = []string{"icalendar", "vcalendar", "components", "vevent", "properties"}
+
+// NewEventExtension creates an EventExtension given an
+// extension map for the "xcal" key. Dates and coordinates
+// that can't be parsed are left out.
+func NewEventExtension(extensions map[string][]Extension) *EventExtension {
+	extensions = xcalProperties(extensions)
+	event := &EventExtension{}
+	event.Start = parseXCalTime(xcalValue("dtstart", extensions))
+	event.End = parseXCalTime(xcalValue("dtend", extensions))
+	event.Location = xcalValue("location", extensions)
+	event.Summary = xcalValue("summary", extensions)
+	event.Geo = parseXCalGeo(extensions)
+	return event
+}
+
+// xcalProperties returns the properties of the event. RFC
+// 6321 nests them in vcalendar/components/vevent/properties,
+// which a feed may embed from any level on, while the older
+// draft puts them straight in the item.
+func xcalProperties(extensions map[string][]Extension) map[string][]Extension {
+	for _, name := range xcalEventPath {
+		if matches, ok := extensions[name]; ok && len(matches) > 0 {
+			extensions = matches[0].Children
+		}
+	}
+	return extensions
+}
+
+// xcalValue returns the trimmed value of the named property.
+// RFC 6321 wraps values in an element naming their type, e.g.
+// <dtstart><date-time>…</date-time></dtstart>, while older
+// feeds put them in the property itself.
+func xcalValue(name string, extensions map[string][]Extension) string {
+	matches, ok := extensions[name]
+	if !ok || len(matches) == 0 {
+		return ""
+	}
+	match := matches[0]
+	if value := strings.TrimSpace(match.Value); value != "" {
+		return value
+	}
+	for _, typ := range []string{"date-time", "date", "text"} {
+		if value := strings.TrimSpace(parseTextExtension(typ, match.Children)); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func parseXCalTime(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	for _, layout := range xcalTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+// parseXCalGeo parses the geo property, either as the
+// latitude and longitude elements of RFC 6321 or as the
+// "lat;lon" text of iCalendar.
+func parseXCalGeo(extensions map[string][]Extension) *GeoPoint {
+	matches, ok := extensions["geo"]
+	if !ok || len(matches) == 0 {
+		return nil
+	}
+	match := matches[0]
+
+	lat := strings.TrimSpace(parseTextExtension("latitude", match.Children))
+	lon := strings.TrimSpace(parseTextExtension("longitude", match.Children))
+	if lat == "" && lon == "" {
+		lat, lon, _ = strings.Cut(strings.TrimSpace(match.Value), ";")
+	}
+
+	latf, err := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	if err != nil || latf < -90 || latf > 90 {
+		return nil
+	}
+	lonf, err := strconv.ParseFloat(strings.TrimSpace(lon), 64)
+	if err != nil || lonf < -180 || lonf > 180 {
+		return nil
+	}
+	return &GeoPoint{Lat: latf, Lon: lonf}
+}
@@ -2,9 +2,14 @@ package gofeed
 
 import (
 	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	ext "github.com/mmcdole/gofeed/extensions"
+	"github.com/mmcdole/gofeed/internal/shared"
+	"golang.org/x/net/html"
 )
 
 // Feed is the universal Feed type that atom.Feed
@@ -12,60 +17,101 @@ import (
 // a web feed.
 // Sorting with sort.Sort will order the Items by
 // oldest to newest publish time.
+//
+// A Feed can be cached with encoding/json: empty fields are
+// omitted, parsed times are written as RFC 3339 and decoding
+// the output yields an equal Feed.
 type Feed struct {
-	Title           string                   `json:"title,omitempty"`
-	Description     string                   `json:"description,omitempty"`
-	Link            string                   `json:"link,omitempty"`
-	FeedLink        string                   `json:"feedLink,omitempty"`
-	Links           []string                 `json:"links,omitempty"`
-	Updated         string                   `json:"updated,omitempty"`
-	UpdatedParsed   *time.Time               `json:"updatedParsed,omitempty"`
-	Published       string                   `json:"published,omitempty"`
-	PublishedParsed *time.Time               `json:"publishedParsed,omitempty"`
-	Author          *Person                  `json:"author,omitempty"` // Deprecated: Use feed.Authors instead
-	Authors         []*Person                `json:"authors,omitempty"`
-	Language        string                   `json:"language,omitempty"`
-	Image           *Image                   `json:"image,omitempty"`
-	Copyright       string                   `json:"copyright,omitempty"`
-	Generator       string                   `json:"generator,omitempty"`
-	Categories      []string                 `json:"categories,omitempty"`
-	DublinCoreExt   *ext.DublinCoreExtension `json:"dcExt,omitempty"`
-	ITunesExt       *ext.ITunesFeedExtension `json:"itunesExt,omitempty"`
-	Extensions      ext.Extensions           `json:"extensions,omitempty"`
-	Custom          map[string]string        `json:"custom,omitempty"`
-	Items           []*Item                  `json:"items"`
-	FeedType        string                   `json:"feedType"`
-	FeedVersion     string                   `json:"feedVersion"`
-}
-
-func (f Feed) String() string {
-	json, _ := json.MarshalIndent(f, "", "    ")
-	return string(json)
+	Title              string                        `json:"title,omitempty"`
+	Description        string                        `json:"description,omitempty"`
+	Link               string                        `json:"link,omitempty"`
+	FeedLink           string                        `json:"feedLink,omitempty"`
+	Hubs               []string                      `json:"hubs,omitempty"`
+	Links              []string                      `json:"links,omitempty"`
+	RelLinks           []*Link                       `json:"relLinks,omitempty"`
+	ID                 string                        `json:"id,omitempty"`
+	Updated            string                        `json:"updated,omitempty"`
+	UpdatedParsed      *time.Time                    `json:"updatedParsed,omitempty"`
+	Published          string                        `json:"published,omitempty"`
+	PublishedParsed    *time.Time                    `json:"publishedParsed,omitempty"`
+	Author             *Person                       `json:"author,omitempty"` // Deprecated: Use feed.Authors instead
+	Authors            []*Person                     `json:"authors,omitempty"`
+	Contributors       []*Person                     `json:"contributors,omitempty"`
+	ManagingEditor     *Person                       `json:"managingEditor,omitempty"`
+	WebMaster          *Person                       `json:"webMaster,omitempty"`
+	Language           string                        `json:"language,omitempty"`
+	RawLanguage        string                        `json:"rawLanguage,omitempty"`
+	Image              *Image                        `json:"image,omitempty"`
+	Icon               string                        `json:"icon,omitempty"`
+	Copyright          string                        `json:"copyright,omitempty"`
+	Generator          string                        `json:"generator,omitempty"`
+	GeneratorInfo      *Generator                    `json:"generatorInfo,omitempty"`
+	Categories         []string                      `json:"categories,omitempty"`
+	ContentRating      *ContentRating                `json:"contentRating,omitempty"`
+	TTL                time.Duration                 `json:"ttl,omitempty"`
+	Complete           bool                          `json:"complete,omitempty"` // RFC 5005 fh:complete
+	Archive            bool                          `json:"archive,omitempty"`  // RFC 5005 fh:archive
+	DublinCoreExt      *ext.DublinCoreExtension      `json:"dcExt,omitempty"`
+	DublinCoreTermsExt *ext.DublinCoreTermsExtension `json:"dctermsExt,omitempty"`
+	ITunesExt          *ext.ITunesFeedExtension      `json:"itunesExt,omitempty"`
+	Extensions         ext.Extensions                `json:"extensions,omitempty"`
+	JSONExtensions     map[string]json.RawMessage    `json:"jsonExtensions,omitempty"`
+	Namespaces         map[string]string             `json:"namespaces,omitempty"` // Declared on the root element, url to prefix
+	Custom             map[string]string             `json:"custom,omitempty"`
+	ItemCount          int                           `json:"itemCount,omitempty"` // Only set with Parser.MetadataOnly
+	Items              []*Item                       `json:"items"`
+	FeedType           string                        `json:"feedType"`
+	FeedVersion        string                        `json:"feedVersion"`
+	Bozo               bool                          `json:"bozo,omitempty"` // Not well-formed or has Warnings, with the first issue in BozoErr
+	BozoErr            error                         `json:"-"`
+	Warnings           []ParseWarning                `json:"warnings,omitempty"`
 }
 
 // Item is the universal Item type that atom.Entry
 // and rss.Item gets translated to.  It represents
 // a single entry in a given feed.
 type Item struct {
-	Title           string                   `json:"title,omitempty"`
-	Description     string                   `json:"description,omitempty"`
-	Content         string                   `json:"content,omitempty"`
-	Link            string                   `json:"link,omitempty"`
-	Links           []string                 `json:"links,omitempty"`
-	Updated         string                   `json:"updated,omitempty"`
-	UpdatedParsed   *time.Time               `json:"updatedParsed,omitempty"`
-	Published       string                   `json:"published,omitempty"`
-	PublishedParsed *time.Time               `json:"publishedParsed,omitempty"`
-	Author          *Person                  `json:"author,omitempty"` // Deprecated: Use item.Authors instead
-	Authors         []*Person                `json:"authors,omitempty"`
-	GUID            string                   `json:"guid,omitempty"`
-	Image           *Image                   `json:"image,omitempty"`
-	Categories      []string                 `json:"categories,omitempty"`
-	Enclosures      []*Enclosure             `json:"enclosures,omitempty"`
-	DublinCoreExt   *ext.DublinCoreExtension `json:"dcExt,omitempty"`
-	ITunesExt       *ext.ITunesItemExtension `json:"itunesExt,omitempty"`
-	Extensions      ext.Extensions           `json:"extensions,omitempty"`
-	Custom          map[string]string        `json:"custom,omitempty"`
+	Title              string                        `json:"title,omitempty"`
+	Description        string                        `json:"description,omitempty"`
+	DescriptionType    string                        `json:"descriptionType,omitempty"` // "text", "html" or "xhtml"
+	Content            string                        `json:"content,omitempty"`
+	ContentType        string                        `json:"contentType,omitempty"` // "text", "html", "xhtml" or the media type of Atom content
+	Link               string                        `json:"link,omitempty"`
+	Links              []string                      `json:"links,omitempty"`
+	RelLinks           []*Link                       `json:"relLinks,omitempty"`
+	Updated            string                        `json:"updated,omitempty"`
+	UpdatedParsed      *time.Time                    `json:"updatedParsed,omitempty"`
+	Published          string                        `json:"published,omitempty"`
+	PublishedParsed    *time.Time                    `json:"publishedParsed,omitempty"`
+	Author             *Person                       `json:"author,omitempty"` // Deprecated: Use item.Authors instead
+	Authors            []*Person                     `json:"authors,omitempty"`
+	Contributors       []*Person                     `json:"contributors,omitempty"`
+	GUID               string                        `json:"guid,omitempty"`
+	Index              int                           `json:"index,omitempty"` // Position in the source document, starting at 0
+	Language           string                        `json:"language,omitempty"`
+	RawLanguage        string                        `json:"rawLanguage,omitempty"`
+	Image              *Image                        `json:"image,omitempty"`
+	BannerImage        *Image                        `json:"bannerImage,omitempty"` // JSON Feed banner_image
+	Copyright          string                        `json:"copyright,omitempty"`
+	Categories         []string                      `json:"categories,omitempty"`
+	ContentRating      *ContentRating                `json:"contentRating,omitempty"`
+	Enclosures         []*Enclosure                  `json:"enclosures,omitempty"`
+	DublinCoreExt      *ext.DublinCoreExtension      `json:"dcExt,omitempty"`
+	DublinCoreTermsExt *ext.DublinCoreTermsExtension `json:"dctermsExt,omitempty"`
+	ITunesExt          *ext.ITunesItemExtension      `json:"itunesExt,omitempty"`
+	MediaExt           *ext.MediaExtension           `json:"mediaExt,omitempty"`
+	ThreadingExt       *ext.ThreadingExtension       `json:"thrExt,omitempty"`
+	GeoExt             *ext.GeoExtension             `json:"geoExt,omitempty"`
+	EventExt           *ext.EventExtension           `json:"eventExt,omitempty"`
+	InReplyTo          []string                      `json:"inReplyTo,omitempty"`
+	CommentsURL        string                        `json:"commentsURL,omitempty"`  // URL of the comment feed
+	CommentsLink       string                        `json:"commentsLink,omitempty"` // URL of the HTML page with the comments
+	CommentCount       int                           `json:"commentCount,omitempty"`
+	SourceFeed         *SourceFeed                   `json:"sourceFeed,omitempty"`
+	Replies            []*Item                       `json:"-"` // Filled in by BuildThread
+	Extensions         ext.Extensions                `json:"extensions,omitempty"`
+	JSONExtensions     map[string]json.RawMessage    `json:"jsonExtensions,omitempty"`
+	Custom             map[string]string             `json:"custom,omitempty"`
 }
 
 // Person is an individual specified in a feed
@@ -73,6 +119,20 @@ type Item struct {
 type Person struct {
 	Name  string `json:"name,omitempty"`
 	Email string `json:"email,omitempty"`
+	URI   string `json:"uri,omitempty"`
+}
+
+// Link is a link element along with its relation and the
+// attributes describing the linked resource. Unlike the
+// plain Links, it tells apart e.g. alternates in different
+// languages or formats.
+type Link struct {
+	Href     string `json:"href,omitempty"`
+	Rel      string `json:"rel,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Hreflang string `json:"hreflang,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Length   string `json:"length,omitempty"`
 }
 
 // Image is an image that is the artwork for a given
@@ -82,11 +142,64 @@ type Image struct {
 	Title string `json:"title,omitempty"`
 }
 
+// SourceFeed names the feed an aggregated Item was
+// originally published in, from an RSS or Atom <source>.
+type SourceFeed struct {
+	Title string `json:"title,omitempty"`
+	URL   string `json:"url,omitempty"`
+}
+
+// Generator identifies the software that produced
+// a feed.
+type Generator struct {
+	Value   string `json:"value,omitempty"`
+	URI     string `json:"uri,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
 // Enclosure is a file associated with a given Item.
+// TypeInferred is set when Type was guessed from the
+// extension of URL rather than given by the feed.
 type Enclosure struct {
-	URL    string `json:"url,omitempty"`
-	Length string `json:"length,omitempty"`
-	Type   string `json:"type,omitempty"`
+	URL          string `json:"url,omitempty"`
+	Length       string `json:"length,omitempty"`
+	LengthInt    int64  `json:"lengthInt,omitempty"`
+	Type         string `json:"type,omitempty"`
+	TypeInferred bool   `json:"typeInferred,omitempty"`
+}
+
+// ParseWarning describes a non-fatal issue that was
+// encountered while parsing a feed, such as a date that
+// could not be parsed or a duplicate item identifier.
+type ParseWarning struct {
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+func (w ParseWarning) String() string {
+	return w.Path + ": " + w.Message
+}
+
+// Error makes a warning usable as the BozoErr of a feed.
+func (w ParseWarning) Error() string {
+	return w.String()
+}
+
+// ParseError is returned when a feed can not be parsed. It
+// records where in the document parsing stopped.
+type ParseError struct {
+	Offset int64  // Byte offset into the document
+	Line   int    // Line number, starting at 1
+	Path   string // Element path (e.g. "rss > channel > item[3] > pubDate")
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d, offset %d: %s: %v", e.Line, e.Offset, e.Path, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
 }
 
 // Len returns the length of Items.
@@ -106,3 +219,373 @@ func (f Feed) Less(i, k int) bool {
 func (f Feed) Swap(i, k int) {
 	f.Items[i], f.Items[k] = f.Items[k], f.Items[i]
 }
+
+// FirstImage returns the src of the first <img> in the item's
+// Content, or its Description if Content has none, e.g. to
+// show a thumbnail for items without an Image. Relative urls
+// are resolved against the item's Link, or the Link and then
+// FeedLink of feed, the feed the item belongs to, when the
+// item has no absolute Link. It returns an empty string when
+// there is no image.
+func (i *Item) FirstImage(feed *Feed) string {
+	for _, document := range []string{i.Content, i.Description} {
+		if src := firstImageSrc(document); src != "" {
+			return resolveAgainst(i.imageBase(feed), src)
+		}
+	}
+	return ""
+}
+
+// imageBase returns the url relative image urls of the item
+// are resolved against, see FirstImage.
+func (i *Item) imageBase(feed *Feed) string {
+	bases := []string{i.Link}
+	if feed != nil {
+		bases = append(bases, feed.Link, feed.FeedLink)
+	}
+	for _, base := range bases {
+		if u, err := url.Parse(base); err == nil && u.IsAbs() {
+			return base
+		}
+	}
+	return ""
+}
+
+// BestImage returns the absolute url of the image that best
+// represents the item, checking in order: Image, the media
+// thumbnails of the item, its groups and contents, media
+// contents that are images, the itunes:image, image
+// enclosures, an og:image meta tag in Content or Description
+// and finally FirstImage. Relative urls are resolved as
+// FirstImage does, using feed when the item has no absolute
+// Link, and skipped if that doesn't make them absolute. It returns an empty string when there is no
+// image; feeds usually have an Image of their own to fall
+// back to.
+func (i *Item) BestImage(feed *Feed) string {
+	base := i.imageBase(feed)
+	var candidates []string
+	if i.Image != nil {
+		candidates = append(candidates, i.Image.URL)
+	}
+	if i.MediaExt != nil {
+		candidates = append(candidates, mediaImages(i.MediaExt)...)
+	}
+	if i.ITunesExt != nil {
+		candidates = append(candidates, i.ITunesExt.Image)
+	}
+	for _, enc := range i.Enclosures {
+		if enc != nil && strings.HasPrefix(strings.ToLower(enc.Type), "image/") {
+			candidates = append(candidates, enc.URL)
+		}
+	}
+	for _, c := range candidates {
+		if image := absoluteImage(base, c); image != "" {
+			return image
+		}
+	}
+
+	// Only look through the HTML when nothing else matched
+	if image := absoluteImage(base, ogImage(i.Content)); image != "" {
+		return image
+	}
+	if image := absoluteImage(base, ogImage(i.Description)); image != "" {
+		return image
+	}
+	return absoluteImage(base, i.FirstImage(feed))
+}
+
+// absoluteImage resolves an image url against base, returning
+// an empty string unless that makes it an absolute http(s)
+// url.
+func absoluteImage(base, image string) string {
+	if image = strings.TrimSpace(image); image == "" {
+		return ""
+	}
+	u, err := url.Parse(resolveAgainst(base, image))
+	if err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" {
+		return u.String()
+	}
+	return ""
+}
+
+// mediaImages returns the thumbnail urls of a media extension,
+// followed by the urls of the media contents that are images.
+func mediaImages(media *ext.MediaExtension) (images []string) {
+	contents := allMediaContents(media)
+	for _, t := range media.Thumbnails {
+		images = append(images, t.URL)
+	}
+	for _, g := range media.Groups {
+		for _, t := range g.Thumbnails {
+			images = append(images, t.URL)
+		}
+	}
+	for _, c := range contents {
+		for _, t := range c.Thumbnails {
+			images = append(images, t.URL)
+		}
+	}
+	for _, c := range contents {
+		if strings.EqualFold(c.Medium, "image") || strings.HasPrefix(strings.ToLower(c.Type), "image/") {
+			images = append(images, c.URL)
+		}
+	}
+	return
+}
+
+// allMediaContents returns the media contents of a media
+// extension followed by those of its groups, in a slice of
+// its own so appending never touches media.Contents.
+func allMediaContents(media *ext.MediaExtension) []*ext.MediaContent {
+	contents := append([]*ext.MediaContent(nil), media.Contents...)
+	for _, g := range media.Groups {
+		contents = append(contents, g.Contents...)
+	}
+	return contents
+}
+
+// ogImage returns the content of the first og:image meta tag
+// of an HTML document.
+func ogImage(document string) string {
+	if !strings.Contains(document, "og:image") {
+		return ""
+	}
+	z := html.NewTokenizer(strings.NewReader(document))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return ""
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			if string(name) != "meta" {
+				continue
+			}
+			var property, content string
+			for hasAttr {
+				var key, val []byte
+				key, val, hasAttr = z.TagAttr()
+				switch string(key) {
+				case "property":
+					property = strings.TrimSpace(string(val))
+				case "content":
+					content = string(val)
+				}
+			}
+			if property == "og:image" || property == "og:image:url" {
+				return content
+			}
+		}
+	}
+}
+
+// firstImageSrc returns the trimmed src of the first img
+// element of an HTML document that has one.
+func firstImageSrc(document string) string {
+	// Without any markup there can't be an img element
+	if !strings.Contains(document, "<") {
+		return ""
+	}
+	z := html.NewTokenizer(strings.NewReader(document))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return ""
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			if string(name) != "img" {
+				continue
+			}
+			for hasAttr {
+				var key, val []byte
+				key, val, hasAttr = z.TagAttr()
+				if string(key) == "src" {
+					if src := strings.TrimSpace(string(val)); src != "" {
+						return src
+					}
+				}
+			}
+		}
+	}
+}
+
+// resolveAgainst resolves ref against base, leaving it as it
+// is when base isn't an absolute url.
+func resolveAgainst(base, ref string) string {
+	r, err := url.Parse(ref)
+	if err != nil || r.IsAbs() {
+		return ref
+	}
+	b, err := url.Parse(base)
+	if err != nil || !b.IsAbs() {
+		return ref
+	}
+	return b.ResolveReference(r).String()
+}
+
+// LinkByRel returns the href of the item's first RelLinks
+// entry with the given relation, e.g. "edit" to find where an
+// AtomPub client can update the entry. Relations are matched
+// exactly as they appear in the feed. It returns an empty
+// string when there is no such link.
+func (i *Item) LinkByRel(rel string) string {
+	return relLink(i.RelLinks, rel)
+}
+
+// relLink returns the href of the first of links with the
+// given relation.
+func relLink(links []*Link, rel string) string {
+	for _, l := range links {
+		if l != nil && l.Rel == rel {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+// ExtensionByNamespace returns the element extensions of the
+// feed in the namespace with the given url, whatever prefix
+// the feed used for it, e.g. "http://purl.org/dc/elements/1.1/"
+// and "creator". Extensions are keyed by their
+// registered or canonical prefix, or else the one declared on
+// the root element, which is looked up in Namespaces.
+func (f *Feed) ExtensionByNamespace(namespace, element string) []ext.Extension {
+	return extensionByNamespace(f.Extensions, f.Namespaces, namespace, element)
+}
+
+// ExtensionByNamespace is Feed.ExtensionByNamespace for the
+// extensions of the item. feed, the feed the item belongs
+// to, supplies the namespaces declared on its root element;
+// it may be nil when looking up a namespace with a registered
+// or canonical prefix.
+func (i *Item) ExtensionByNamespace(feed *Feed, namespace, element string) []ext.Extension {
+	var declared map[string]string
+	if feed != nil {
+		declared = feed.Namespaces
+	}
+	return extensionByNamespace(i.Extensions, declared, namespace, element)
+}
+
+func extensionByNamespace(extensions ext.Extensions, declared map[string]string, namespace, element string) []ext.Extension {
+	return extensions[shared.ResolvePrefix(namespace, declared)][element]
+}
+
+// Via returns the href of the item's rel="via" link, the
+// source the entry was aggregated from. Unlike an Atom
+// <source> element it only names the page, not the feed. It
+// returns an empty string when there is no such link.
+func (i *Item) Via() string {
+	return i.LinkByRel("via")
+}
+
+// MediaByMedium returns the media contents of the item and
+// of its media groups whose medium is the given one, e.g.
+// "image", "audio", "video", "document" or "executable".
+// Contents without a medium attribute are matched by the top
+// level of their media type instead, so a group with an
+// image/jpeg thumbnail and an audio/mpeg file can be told
+// apart either way.
+func (i *Item) MediaByMedium(medium string) (contents []*ext.MediaContent) {
+	if i.MediaExt == nil {
+		return nil
+	}
+	for _, c := range allMediaContents(i.MediaExt) {
+		if c != nil && strings.EqualFold(contentMedium(c), medium) {
+			contents = append(contents, c)
+		}
+	}
+	return
+}
+
+// Images returns the media contents of the item that are
+// images, see MediaByMedium.
+func (i *Item) Images() []*ext.MediaContent {
+	return i.MediaByMedium("image")
+}
+
+// Audio returns the media contents of the item that are
+// audio, see MediaByMedium.
+func (i *Item) Audio() []*ext.MediaContent {
+	return i.MediaByMedium("audio")
+}
+
+// Video returns the media contents of the item that are
+// videos, see MediaByMedium.
+func (i *Item) Video() []*ext.MediaContent {
+	return i.MediaByMedium("video")
+}
+
+// contentMedium returns the medium of c, falling back to the
+// top level of its media type for the mediums that have one.
+func contentMedium(c *ext.MediaContent) string {
+	if c.Medium != "" {
+		return strings.TrimSpace(c.Medium)
+	}
+	top, _, _ := strings.Cut(strings.ToLower(c.Type), "/")
+	switch top {
+	case "image", "audio", "video":
+		return top
+	}
+	return ""
+}
+
+// ResolvedITunes returns the item's itunes fields with the
+// author, image, explicit and block values it doesn't set
+// inherited from feed, the feed the item belongs to. The
+// item's ITunesExt keeps only what the episode declares. It
+// returns nil when neither has itunes fields.
+func (i *Item) ResolvedITunes(feed *Feed) *ext.ITunesItemExtension {
+	var feedExt *ext.ITunesFeedExtension
+	if feed != nil {
+		feedExt = feed.ITunesExt
+	}
+	return ext.ResolveITunes(feedExt, i.ITunesExt)
+}
+
+// BuildThread arranges items into reply trees using their
+// GUID and InReplyTo refs, e.g. to display the comments feed
+// of a post. It returns the items that aren't replies to any
+// other item in the set, in their original order, with the
+// Replies of every item filled in. Items replying to several
+// items in the set are attached to the first one only.
+func BuildThread(items []*Item) []*Item {
+	byGUID := make(map[string]*Item, len(items))
+	for _, item := range items {
+		item.Replies = nil
+		if item.GUID != "" && byGUID[item.GUID] == nil {
+			byGUID[item.GUID] = item
+		}
+	}
+
+	parents := make(map[*Item]*Item, len(items))
+	for _, item := range items {
+		for _, ref := range item.InReplyTo {
+			parent := byGUID[ref]
+			if parent == nil || isAncestor(parents, item, parent) {
+				continue
+			}
+			parents[item] = parent
+			break
+		}
+	}
+
+	roots := []*Item{}
+	for _, item := range items {
+		if parent, ok := parents[item]; ok {
+			parent.Replies = append(parent.Replies, item)
+		} else {
+			roots = append(roots, item)
+		}
+	}
+	return roots
+}
+
+// isAncestor reports whether item is node or one of its
+// ancestors, so that a reply cycle never drops its items.
+func isAncestor(parents map[*Item]*Item, item, node *Item) bool {
+	for ; node != nil; node = parents[node] {
+		if node == item {
+			return true
+		}
+	}
+	return false
+}
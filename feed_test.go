@@ -1,11 +1,16 @@
 package gofeed_test
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"sort"
 	"testing"
 	"time"
 
 	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestFeedSort(t *testing.T) {
@@ -48,3 +53,362 @@ func TestFeedSort(t *testing.T) {
 		}
 	}
 }
+
+func TestFeedString(t *testing.T) {
+	feed := gofeed.Feed{
+		Title:       "Feed Title",
+		FeedType:    "rss",
+		FeedVersion: "2.0",
+		Items: []*gofeed.Item{
+			{Title: "One"},
+			{Title: "Two"},
+			{Title: "Three"},
+			{Title: "Four"},
+			{Title: "Five"},
+		},
+	}
+
+	expected := "\"Feed Title\" (rss 2.0) with 5 items\n" +
+		"  - \"One\"\n" +
+		"  - \"Two\"\n" +
+		"  - \"Three\"\n" +
+		"  ... and 2 more"
+
+	if got := feed.String(); got != expected {
+		t.Errorf("Feed.String() = %q; want %q", got, expected)
+	}
+}
+
+func TestFeedDump(t *testing.T) {
+	feed := gofeed.Feed{
+		Title:       "Feed Title",
+		FeedType:    "rss",
+		FeedVersion: "2.0",
+		Items: []*gofeed.Item{
+			{
+				Title: "Item Title",
+				Extensions: ext.Extensions{
+					"media": {
+						"group": []ext.Extension{
+							{
+								Name: "group",
+								Children: map[string][]ext.Extension{
+									"content": {
+										{
+											Name:  "content",
+											Attrs: map[string]string{"url": "http://example.org/a.mp4", "type": "video/mp4"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	expected := "feed rss 2.0\n" +
+		"  title: \"Feed Title\"\n" +
+		"  item[0]\n" +
+		"    title: \"Item Title\"\n" +
+		"    media:group\n" +
+		"      media:content type=\"video/mp4\" url=\"http://example.org/a.mp4\"\n"
+
+	if got := feed.Dump(); got != expected {
+		t.Errorf("Feed.Dump() = %q; want %q", got, expected)
+	}
+}
+
+func TestFeedJSON(t *testing.T) {
+	updated := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	feed := &gofeed.Feed{
+		Title:              "Feed Title",
+		Link:               "http://example.org/",
+		FeedLink:           "http://example.org/feed",
+		Hubs:               []string{"http://example.org/hub"},
+		Links:              []string{"http://example.org/"},
+		Updated:            "Mon, 02 Jan 2006 15:04:05 GMT",
+		UpdatedParsed:      &updated,
+		Authors:            []*gofeed.Person{{Name: "Author", Email: "author@example.org", URI: "http://example.org/author"}},
+		ManagingEditor:     &gofeed.Person{Email: "editor@example.org"},
+		Image:              &gofeed.Image{URL: "http://example.org/image.png", Title: "Image"},
+		Icon:               "http://example.org/favicon.ico",
+		GeneratorInfo:      &gofeed.Generator{Value: "Generator", URI: "http://example.org/generator", Version: "1.0"},
+		Categories:         []string{"News"},
+		TTL:                time.Hour,
+		DublinCoreExt:      &ext.DublinCoreExtension{Creator: []string{"Creator"}},
+		DublinCoreTermsExt: &ext.DublinCoreTermsExtension{Modified: "2006-01-02T15:04:05Z", ModifiedParsed: &updated},
+		ITunesExt:          &ext.ITunesFeedExtension{Image: "http://example.org/podcast.jpg", Owner: &ext.ITunesOwner{Name: "Owner"}},
+		Extensions: ext.Extensions{
+			"x": {"rating": []ext.Extension{{Name: "rating", Value: "5", Attrs: map[string]string{"scheme": "stars"}}}},
+		},
+		Custom: map[string]string{"key": "value"},
+		Items: []*gofeed.Item{
+			{
+				Title:           "Item Title",
+				Content:         "<p>Content</p>",
+				GUID:            "urn:item",
+				PublishedParsed: &updated,
+				Enclosures:      []*gofeed.Enclosure{{URL: "http://example.org/a.mp3", Length: "100", LengthInt: 100, Type: "audio/mpeg"}},
+				ITunesExt:       &ext.ITunesItemExtension{Duration: "1:00"},
+				MediaExt:        &ext.MediaExtension{Contents: []*ext.MediaContent{{URL: "http://example.org/a.mp4"}}},
+			},
+		},
+		FeedType:    "rss",
+		FeedVersion: "2.0",
+		Warnings:    []gofeed.ParseWarning{{Path: "rss > channel > pubDate", Message: "unparseable date"}},
+	}
+
+	data, err := json.Marshal(feed)
+	assert.Nil(t, err)
+
+	s := string(data)
+	assert.Contains(t, s, `"updatedParsed":"2006-01-02T15:04:05Z"`)
+	assert.Contains(t, s, `"extensions":{"x":{"rating":[{"name":"rating","value":"5"`)
+	assert.NotContains(t, s, `"description"`)
+	assert.NotContains(t, s, `"author"`)
+
+	decoded := &gofeed.Feed{}
+	assert.Nil(t, json.Unmarshal(data, decoded))
+	assert.Equal(t, feed, decoded)
+}
+
+func TestFeedJSON_RoundTrip(t *testing.T) {
+	files, _ := filepath.Glob("testdata/translator/*/*.xml")
+	extensionFiles, _ := filepath.Glob("testdata/extensions/*/*.xml")
+	files = append(files, extensionFiles...)
+
+	fp := gofeed.NewParser()
+	for _, f := range files {
+		data, _ := os.ReadFile(f)
+		feed, err := fp.ParseBytes(data)
+		if err != nil {
+			continue
+		}
+
+		encoded, err := json.Marshal(feed)
+		assert.Nil(t, err, f)
+
+		decoded := &gofeed.Feed{}
+		assert.Nil(t, json.Unmarshal(encoded, decoded), f)
+		// BozoErr is an error, which has no JSON form
+		feed.BozoErr = nil
+		assert.Equal(t, feed, decoded, f)
+	}
+}
+
+func TestBuildThread(t *testing.T) {
+	post := &gofeed.Item{GUID: "post"}
+	comment := &gofeed.Item{GUID: "c1", InReplyTo: []string{"post"}}
+	reply := &gofeed.Item{GUID: "c2", InReplyTo: []string{"c1"}}
+	second := &gofeed.Item{GUID: "c3", InReplyTo: []string{"post", "c1"}}
+	orphan := &gofeed.Item{GUID: "c4", InReplyTo: []string{"elsewhere"}}
+	// Replies to each other, neither may get lost
+	cycleA := &gofeed.Item{GUID: "a", InReplyTo: []string{"b"}}
+	cycleB := &gofeed.Item{GUID: "b", InReplyTo: []string{"a"}}
+
+	roots := gofeed.BuildThread([]*gofeed.Item{reply, post, comment, second, orphan, cycleA, cycleB})
+
+	assert.Equal(t, []*gofeed.Item{post, orphan, cycleB}, roots)
+	assert.Equal(t, []*gofeed.Item{comment, second}, post.Replies)
+	assert.Equal(t, []*gofeed.Item{reply}, comment.Replies)
+	assert.Empty(t, reply.Replies)
+	assert.Empty(t, orphan.Replies)
+	assert.Equal(t, []*gofeed.Item{cycleA}, cycleB.Replies)
+
+	// Building again starts over rather than adding to Replies
+	roots = gofeed.BuildThread([]*gofeed.Item{post, comment})
+	assert.Equal(t, []*gofeed.Item{post}, roots)
+	assert.Equal(t, []*gofeed.Item{comment}, post.Replies)
+}
+
+func TestItem_FirstImage(t *testing.T) {
+	var imageTests = []struct {
+		item     gofeed.Item
+		expected string
+	}{
+		{gofeed.Item{Content: `<p>Hi <img src="http://example.org/a.png"> <img src="http://example.org/b.png"></p>`}, "http://example.org/a.png"},
+		{gofeed.Item{Content: `<p>No image</p>`, Description: `<img alt="" src="http://example.org/d.png"/>`}, "http://example.org/d.png"},
+		{gofeed.Item{Content: `<img src="">`, Description: `<IMG SRC="http://example.org/d.png">`}, "http://example.org/d.png"},
+		{gofeed.Item{Link: "http://example.org/posts/1", Content: `<img src="../images/a.png">`}, "http://example.org/images/a.png"},
+		{gofeed.Item{Content: `<img src="images/a.png">`}, "images/a.png"},
+		{gofeed.Item{Content: `&lt;img src="http://example.org/a.png"&gt;`}, ""},
+		{gofeed.Item{Description: "plain text"}, ""},
+		{gofeed.Item{}, ""},
+	}
+
+	for _, test := range imageTests {
+		assert.Equal(t, test.expected, test.item.FirstImage(nil), test.item.Content)
+	}
+
+	// Items without an absolute link fall back to the feed
+	item := &gofeed.Item{Content: `<img src="/a.png">`}
+	feed := &gofeed.Feed{Link: "http://example.org/blog/", FeedLink: "http://example.com/feed.xml"}
+	assert.Equal(t, "http://example.org/a.png", item.FirstImage(feed))
+	feed.Link = ""
+	assert.Equal(t, "http://example.com/a.png", item.FirstImage(feed))
+	item.Link = "http://example.net/posts/1"
+	assert.Equal(t, "http://example.net/a.png", item.FirstImage(feed))
+}
+
+func TestItem_BestImage(t *testing.T) {
+	var bestImageTests = []struct {
+		name     string
+		item     gofeed.Item
+		expected string
+	}{
+		{"image", gofeed.Item{
+			Image:     &gofeed.Image{URL: "http://example.org/image.jpg"},
+			ITunesExt: &ext.ITunesItemExtension{Image: "http://example.org/itunes.jpg"},
+		}, "http://example.org/image.jpg"},
+		{"media thumbnail of a group content", gofeed.Item{
+			MediaExt: &ext.MediaExtension{Groups: []*ext.MediaGroup{{Contents: []*ext.MediaContent{
+				{URL: "http://example.org/video.mp4", Thumbnails: []*ext.MediaThumbnail{{URL: "http://example.org/thumb.jpg"}}},
+			}}}},
+			ITunesExt: &ext.ITunesItemExtension{Image: "http://example.org/itunes.jpg"},
+		}, "http://example.org/thumb.jpg"},
+		{"media image content", gofeed.Item{
+			MediaExt: &ext.MediaExtension{Contents: []*ext.MediaContent{
+				{URL: "http://example.org/video.mp4", Medium: "video"},
+				{URL: "http://example.org/photo.jpg", Medium: "image"},
+			}},
+		}, "http://example.org/photo.jpg"},
+		{"image enclosure", gofeed.Item{
+			Enclosures: []*gofeed.Enclosure{
+				{URL: "http://example.org/episode.mp3", Type: "audio/mpeg"},
+				{URL: "http://example.org/cover.png", Type: "image/png"},
+			},
+		}, "http://example.org/cover.png"},
+		{"og:image", gofeed.Item{
+			Link:    "http://example.org/posts/1",
+			Content: `<meta property="og:image" content="/og.jpg"><p><img src="inline.jpg"></p>`,
+		}, "http://example.org/og.jpg"},
+		{"first image in content", gofeed.Item{
+			Link:    "http://example.org/posts/1",
+			Content: `<p><img src="inline.jpg"></p>`,
+		}, "http://example.org/posts/inline.jpg"},
+		{"relative urls are skipped without a link", gofeed.Item{
+			Image:      &gofeed.Image{URL: "/image.jpg"},
+			Enclosures: []*gofeed.Enclosure{{URL: "http://example.org/cover.png", Type: "image/png"}},
+		}, "http://example.org/cover.png"},
+		{"no image", gofeed.Item{Description: "<p>No images here</p>"}, ""},
+	}
+
+	for _, test := range bestImageTests {
+		assert.Equal(t, test.expected, test.item.BestImage(nil), test.name)
+	}
+
+	// Relative urls are usable with the feed to resolve them
+	item := &gofeed.Item{Content: `<p><img src="/inline.jpg"></p>`}
+	assert.Empty(t, item.BestImage(nil))
+	assert.Equal(t, "http://example.org/inline.jpg", item.BestImage(&gofeed.Feed{FeedLink: "http://example.org/feed.xml"}))
+}
+
+func TestItem_LinkByRel(t *testing.T) {
+	item := gofeed.Item{RelLinks: []*gofeed.Link{
+		{Href: "http://example.org/posts/1", Rel: "alternate"},
+		{Href: "http://example.org/edit/1", Rel: "edit"},
+		{Href: "http://example.org/media/1", Rel: "edit-media"},
+		{Href: "http://example.org/edit/2", Rel: "edit"},
+	}}
+
+	assert.Equal(t, "http://example.org/edit/1", item.LinkByRel("edit"))
+	assert.Equal(t, "http://example.org/media/1", item.LinkByRel("edit-media"))
+	assert.Equal(t, "", item.LinkByRel("Edit"))
+	assert.Equal(t, "", item.LinkByRel("replies"))
+}
+
+func TestItem_Via(t *testing.T) {
+	item := gofeed.Item{RelLinks: []*gofeed.Link{
+		{Href: "http://example.org/posts/1", Rel: "alternate"},
+		{Href: "http://example.com/original", Rel: "via", Title: "Example"},
+	}}
+
+	assert.Equal(t, "http://example.com/original", item.Via())
+	assert.Equal(t, "", (&gofeed.Item{}).Via())
+}
+
+func TestItem_MediaByMedium(t *testing.T) {
+	image := &ext.MediaContent{URL: "http://example.org/cover.jpg", Medium: "image"}
+	typed := &ext.MediaContent{URL: "http://example.org/cover.png", Type: "image/png"}
+	audio := &ext.MediaContent{URL: "http://example.org/episode.mp3", Type: "audio/mpeg", Medium: "Audio"}
+	video := &ext.MediaContent{URL: "http://example.org/episode.mp4", Medium: "video"}
+	document := &ext.MediaContent{URL: "http://example.org/notes.pdf", Type: "application/pdf", Medium: "document"}
+	item := &gofeed.Item{MediaExt: &ext.MediaExtension{
+		Contents: []*ext.MediaContent{video},
+		Groups: []*ext.MediaGroup{
+			{Contents: []*ext.MediaContent{image, audio, typed, document}},
+		},
+	}}
+
+	assert.Equal(t, []*ext.MediaContent{image, typed}, item.Images())
+	assert.Equal(t, []*ext.MediaContent{audio}, item.Audio())
+	assert.Equal(t, []*ext.MediaContent{video}, item.Video())
+	assert.Equal(t, []*ext.MediaContent{document}, item.MediaByMedium("document"))
+	assert.Empty(t, item.MediaByMedium("executable"))
+	assert.Empty(t, (&gofeed.Item{}).Images())
+
+	// Spare capacity of Contents is left alone
+	contents := make([]*ext.MediaContent, 1, 8)
+	contents[0] = video
+	item.MediaExt.Contents = contents
+	item.Images()
+	assert.Nil(t, contents[:2][1])
+}
+
+func TestFeed_ExtensionByNamespace(t *testing.T) {
+	feedData := `<rss version="2.0" xmlns:dublin="http://purl.org/dc/elements/1.1/" xmlns:x="http://example.org/ns/custom">
+<channel>
+<x:rating>5</x:rating>
+<item><dublin:creator>Jane</dublin:creator><x:rating>4</x:rating></item>
+</channel>
+</rss>`
+
+	feed, err := gofeed.NewParser().ParseString(feedData)
+	assert.Nil(t, err)
+
+	// dc is keyed by its canonical prefix, the custom
+	// namespace by the prefix the feed declared.
+	rating := feed.ExtensionByNamespace("http://example.org/ns/custom", "rating")
+	if assert.Len(t, rating, 1) {
+		assert.Equal(t, "5", rating[0].Value)
+	}
+	item := feed.Items[0]
+	creator := item.ExtensionByNamespace(nil, "http://purl.org/dc/elements/1.1/", "creator")
+	if assert.Len(t, creator, 1) {
+		assert.Equal(t, "Jane", creator[0].Value)
+	}
+	rating = item.ExtensionByNamespace(feed, "http://example.org/ns/custom", "rating")
+	if assert.Len(t, rating, 1) {
+		assert.Equal(t, "4", rating[0].Value)
+	}
+	assert.Empty(t, item.ExtensionByNamespace(feed, "http://example.org/ns/other", "rating"))
+	assert.Empty(t, item.ExtensionByNamespace(feed, "http://example.org/ns/custom", "missing"))
+}
+
+func TestItem_ResolvedITunes(t *testing.T) {
+	feed := &gofeed.Feed{ITunesExt: &ext.ITunesFeedExtension{
+		Author:   "Feed Author",
+		Image:    "http://example.org/podcast.jpg",
+		Explicit: "false",
+	}}
+	item := &gofeed.Item{ITunesExt: &ext.ITunesItemExtension{
+		Explicit: "true",
+		Episode:  "3",
+	}}
+
+	resolved := item.ResolvedITunes(feed)
+	assert.Equal(t, "Feed Author", resolved.Author)
+	assert.Equal(t, "http://example.org/podcast.jpg", resolved.Image)
+	assert.Equal(t, "true", resolved.Explicit)
+	assert.Equal(t, "3", resolved.Episode)
+
+	// The item's own values are left as declared
+	assert.Equal(t, "", item.ITunesExt.Author)
+	assert.Equal(t, "", item.ITunesExt.Image)
+
+	assert.Equal(t, "Feed Author", (&gofeed.Item{}).ResolvedITunes(feed).Author)
+	assert.Equal(t, "true", item.ResolvedITunes(nil).Explicit)
+	assert.Nil(t, (&gofeed.Item{}).ResolvedITunes(&gofeed.Feed{}))
+}
@@ -1,12 +1,23 @@
 package shared
 
 import (
+	"bufio"
+	"bytes"
+	"fmt"
 	"io"
+	"unicode/utf8"
 
 	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
 )
 
 func NewReaderLabel(label string, input io.Reader) (io.Reader, error) {
+	if e, _ := charset.Lookup(label); e != nil {
+		if _, ok := e.(*charmap.Charmap); ok {
+			return newASCIIReader(input, e), nil
+		}
+	}
 	conv, err := charset.NewReaderLabel(label, input)
 
 	if err != nil {
@@ -17,3 +28,105 @@ func NewReaderLabel(label string, input io.Reader) (io.Reader, error) {
 	//clean := NewXMLSanitizerReader(conv)
 	return conv, nil
 }
+
+// KeepCharset is a CharsetReader that leaves the input as it
+// is, for documents that were already decoded to UTF-8 and
+// whose XML declaration may name their original encoding.
+func KeepCharset(label string, input io.Reader) (io.Reader, error) {
+	return input, nil
+}
+
+// LookupCharset returns the encoding for the given label, or
+// nil if the label names UTF-8 and no decoding is needed.
+func LookupCharset(label string) (encoding.Encoding, error) {
+	e, name := charset.Lookup(label)
+	if e == nil {
+		return nil, fmt.Errorf("unsupported charset %q", label)
+	}
+	if name == "utf-8" {
+		return nil, nil
+	}
+	return e, nil
+}
+
+// DecodeCharset returns input decoded from the given charset
+// along with the CharsetReader to parse it with, so that the
+// encoding named by the XML declaration is ignored. An empty
+// label leaves the decoding to the XML declaration.
+func DecodeCharset(label string, input io.Reader) (io.Reader, func(string, io.Reader) (io.Reader, error), error) {
+	if label == "" {
+		return input, NewReaderLabel, nil
+	}
+	e, err := LookupCharset(label)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, ok := e.(*charmap.Charmap); ok {
+		input = newASCIIReader(input, e)
+	} else if e != nil {
+		input = e.NewDecoder().Reader(input)
+	}
+	return input, KeepCharset, nil
+}
+
+// byteReader is what the XML decoder reads from without
+// buffering it again.
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// asciiReader decodes a single byte encoding lazily. The
+// encodings known to charset all leave ASCII as it is, so
+// documents that are pure ASCII, as most are, are passed
+// through without running the decoder. It only takes over
+// from the first byte outside of ASCII on.
+type asciiReader struct {
+	src byteReader
+	e   encoding.Encoding
+	dec *bufio.Reader
+}
+
+func newASCIIReader(input io.Reader, e encoding.Encoding) *asciiReader {
+	src, ok := input.(byteReader)
+	if !ok {
+		src = bufio.NewReader(input)
+	}
+	return &asciiReader{src: src, e: e}
+}
+
+// decodeFrom switches to decoding, starting with pending,
+// the bytes already read from src.
+func (r *asciiReader) decodeFrom(pending []byte) {
+	rest := io.MultiReader(bytes.NewReader(pending), r.src)
+	r.dec = bufio.NewReader(r.e.NewDecoder().Reader(rest))
+}
+
+func (r *asciiReader) Read(b []byte) (int, error) {
+	if r.dec != nil {
+		return r.dec.Read(b)
+	}
+	n, err := r.src.Read(b)
+	for i := 0; i < n; i++ {
+		if b[i] >= utf8.RuneSelf {
+			r.decodeFrom(append([]byte(nil), b[i:n]...))
+			if i > 0 {
+				return i, nil
+			}
+			return r.dec.Read(b)
+		}
+	}
+	return n, err
+}
+
+func (r *asciiReader) ReadByte() (byte, error) {
+	if r.dec != nil {
+		return r.dec.ReadByte()
+	}
+	c, err := r.src.ReadByte()
+	if err != nil || c < utf8.RuneSelf {
+		return c, err
+	}
+	r.decodeFrom([]byte{c})
+	return r.dec.ReadByte()
+}
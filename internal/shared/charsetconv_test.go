@@ -0,0 +1,94 @@
+package shared
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestNewReaderLabel(t *testing.T) {
+	tests := []struct {
+		label string
+		in    string
+		res   string
+	}{
+		{"windows-1252", "", ""},
+		{"windows-1252", "plain ascii", "plain ascii"},
+		{"windows-1252", "Caf\xe9 \x93quoted\x94", "Café “quoted”"},
+		{"iso-8859-1", "\xe9t\xe9", "été"},
+		{"koi8-r", "abc \xc1\xc2\xd7", "abc абв"},
+		{"shift_jis", "abc \x82\xa0", "abc あ"},
+	}
+
+	for _, test := range tests {
+		// Single byte encodings switch to decoding on the
+		// first byte outside of ASCII with Read and ReadByte.
+		for _, readByte := range []bool{false, true} {
+			r, err := NewReaderLabel(test.label, strings.NewReader(test.in))
+			assert.Nil(t, err, "cannot read %s", test.label)
+
+			var res []byte
+			if br, ok := r.(io.ByteReader); readByte && ok {
+				for c, err := br.ReadByte(); err == nil; c, err = br.ReadByte() {
+					res = append(res, c)
+				}
+			} else {
+				res, err = io.ReadAll(iotest.OneByteReader(r))
+				assert.Nil(t, err)
+			}
+			assert.Equal(t, test.res, string(res),
+				"%q in %s was decoded to %q instead of %q",
+				test.in, test.label, res, test.res)
+		}
+	}
+
+	_, err := NewReaderLabel("x-unknown", strings.NewReader(""))
+	assert.NotNil(t, err)
+}
+
+func BenchmarkNewReaderLabel(b *testing.B) {
+	feed := &bytes.Buffer{}
+	feed.WriteString(`<?xml version="1.0" encoding="windows-1252"?><rss version="2.0"><channel><title>Benchmark</title>`)
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(feed, `<item><title>Item %d</title><link>http://example.org/%d</link>`+
+			`<description>&lt;p&gt;The description of item %d.&lt;/p&gt;</description></item>`, i, i, i)
+	}
+	feed.WriteString(`</channel></rss>`)
+	data := feed.Bytes()
+
+	// Read the way the XML decoder does, byte by byte and
+	// through a buffer of its own unless the reader has
+	// ReadByte.
+	readAll := func(r io.Reader) {
+		br, ok := r.(io.ByteReader)
+		if !ok {
+			br = bufio.NewReader(r)
+		}
+		for _, err := br.ReadByte(); err == nil; _, err = br.ReadByte() {
+		}
+	}
+
+	b.Run("ascii", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			r, _ := NewReaderLabel("windows-1252", bufio.NewReader(bytes.NewReader(data)))
+			readAll(r)
+		}
+	})
+	b.Run("transform", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			r := charmap.Windows1252.NewDecoder().Reader(bufio.NewReader(bytes.NewReader(data)))
+			readAll(r)
+		}
+	})
+}
@@ -3,9 +3,79 @@ package shared
 import (
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 )
 
+// Offsets, in seconds east of UTC, of the time zone
+// abbreviations found in feed dates. Go only knows the
+// abbreviations of the local time zone and parses any other
+// one as UTC. Ambiguous abbreviations resolve to the zone
+// listed here: CST is US Central rather than China Standard
+// Time, AST is Atlantic rather than Arabia Standard Time, BST
+// is British Summer Time and IST is India Standard Time.
+var timezoneOffsets = map[string]int{
+	// North America
+	"NST":  -(3*3600 + 1800),
+	"NDT":  -(2*3600 + 1800),
+	"AST":  -4 * 3600,
+	"ADT":  -3 * 3600,
+	"EST":  -5 * 3600,
+	"EDT":  -4 * 3600,
+	"CST":  -6 * 3600,
+	"CDT":  -5 * 3600,
+	"MST":  -7 * 3600,
+	"MDT":  -6 * 3600,
+	"PST":  -8 * 3600,
+	"PDT":  -7 * 3600,
+	"AKST": -9 * 3600,
+	"AKDT": -8 * 3600,
+	"HST":  -10 * 3600,
+	// Europe
+	"WET":  0,
+	"WEST": 1 * 3600,
+	"BST":  1 * 3600,
+	"CET":  1 * 3600,
+	"CEST": 2 * 3600,
+	"MET":  1 * 3600,
+	"MEST": 2 * 3600,
+	"EET":  2 * 3600,
+	"EEST": 3 * 3600,
+	"MSK":  3 * 3600,
+	// Elsewhere
+	"IST": 5*3600 + 1800,
+}
+
+var timezoneOffsetsMu sync.RWMutex
+
+// RegisterTimezone sets the offset, in seconds east of UTC,
+// that dates using the time zone abbreviation are parsed
+// with, overriding the built-in table.
+func RegisterTimezone(abbreviation string, offset int) {
+	timezoneOffsetsMu.Lock()
+	defer timezoneOffsetsMu.Unlock()
+	timezoneOffsets[strings.ToUpper(abbreviation)] = offset
+}
+
+// resolveTimezone moves a date parsed with layout into the
+// time zone named by its abbreviation, if layout reads the
+// zone by name only and the abbreviation is known.
+func resolveTimezone(t time.Time, layout string) (time.Time, bool) {
+	if !strings.Contains(layout, "MST") || strings.Contains(layout, "-07") {
+		return t, false
+	}
+
+	name, _ := t.Zone()
+	timezoneOffsetsMu.RLock()
+	offset, ok := timezoneOffsets[strings.ToUpper(name)]
+	timezoneOffsetsMu.RUnlock()
+	if !ok {
+		return t, false
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(),
+		time.FixedZone(name, offset)), true
+}
+
 // DateFormats taken from github.com/mjibson/goread
 var dateFormats = []string{
 	time.RFC822,  // RSS
@@ -195,6 +265,7 @@ func ParseDate(ds string) (t time.Time, err error) {
 	}
 	for _, f := range dateFormats {
 		if t, err = time.Parse(f, d); err == nil {
+			t, _ = resolveTimezone(t, f)
 			return
 		}
 	}
@@ -203,6 +274,9 @@ func ParseDate(ds string) (t time.Time, err error) {
 		if err != nil {
 			continue
 		}
+		if resolved, ok := resolveTimezone(t, f); ok {
+			return resolved, nil
+		}
 
 		// This is a format match! Now try to load the timezone name
 		loc, err := time.LoadLocation(t.Location().String())
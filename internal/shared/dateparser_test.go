@@ -0,0 +1,54 @@
+package shared
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDate_Timezones(t *testing.T) {
+	tests := []struct {
+		date string
+		utc  string
+	}{
+		{"Mon, 02 Jan 2006 15:04:05 EST", "2006-01-02T20:04:05Z"},
+		{"Mon, 02 Jan 2006 15:04:05 PDT", "2006-01-02T22:04:05Z"},
+		{"Mon, 02 Jan 2006 15:04:05 CET", "2006-01-02T14:04:05Z"},
+		{"Mon, 02 Jan 2006 15:04:05 CST", "2006-01-02T21:04:05Z"},
+		{"Mon, 02 Jan 2006 15:04:05 NST", "2006-01-02T18:34:05Z"},
+		{"2 Jan 2006 15:04:05 CEST", "2006-01-02T13:04:05Z"},
+		{"Mon, 02 Jan 2006 15:04:05 GMT", "2006-01-02T15:04:05Z"},
+		// Numeric offsets win over the abbreviation
+		{"Mon, 02 Jan 2006 15:04:05 EST -0700", "2006-01-02T22:04:05Z"},
+		{"Mon, 02 Jan 2006 15:04:05 -0300", "2006-01-02T18:04:05Z"},
+	}
+
+	for _, test := range tests {
+		date, err := ParseDate(test.date)
+		if assert.NoError(t, err, test.date) {
+			assert.Equal(t, test.utc, date.UTC().Format(time.RFC3339), test.date)
+		}
+	}
+}
+
+func TestParseDate_DateOnly(t *testing.T) {
+	// Dates without a time are midnight UTC
+	for _, date := range []string{"2024-01-02", "January 2, 2024", "02 Jan 2024", "Tue, 02 Jan 2024"} {
+		parsed, err := ParseDate(date)
+		if assert.NoError(t, err, date) {
+			assert.Equal(t, "2024-01-02T00:00:00Z", parsed.Format(time.RFC3339), date)
+		}
+	}
+}
+
+func TestRegisterTimezone(t *testing.T) {
+	defer RegisterTimezone("CST", -6*3600)
+	RegisterTimezone("cst", 8*3600)
+
+	date, err := ParseDate("Mon, 02 Jan 2006 15:04:05 CST")
+	assert.NoError(t, err)
+	assert.Equal(t, "2006-01-02T07:04:05Z", date.UTC().Format(time.RFC3339))
+	_, offset := date.Zone()
+	assert.Equal(t, 8*3600, offset)
+}
@@ -0,0 +1,97 @@
+package shared
+
+import (
+	"strconv"
+	"strings"
+)
+
+const maxElementPathDepth = 8
+
+// ElementPath keeps track of the chain of elements currently
+// being parsed so that diagnostics can point at the offending
+// element (e.g. "rss > channel > item[3] > pubDate").
+//
+// It uses fixed size storage so tracking the path does not
+// allocate. Elements nested deeper than the storage allows
+// are counted but left out of the rendered path.
+type ElementPath struct {
+	names   [maxElementPathDepth]string
+	indexes [maxElementPathDepth]int
+	depth   int
+}
+
+// Push records that the parser entered the named element. A
+// negative index means the element is not rendered with an index.
+func (e *ElementPath) Push(name string, index int) {
+	if e.depth < maxElementPathDepth {
+		e.names[e.depth] = name
+		e.indexes[e.depth] = index
+	}
+	e.depth++
+}
+
+// Pop records that the parser left the innermost element.
+func (e *ElementPath) Pop() {
+	if e.depth > 0 {
+		e.depth--
+	}
+}
+
+// Depth returns the number of elements on the path.
+func (e *ElementPath) Depth() int {
+	return e.depth
+}
+
+// Render returns the current path, optionally
+// followed by the given child element name.
+func (e *ElementPath) Render(child string) string {
+	parts := []string{}
+	for i := 0; i < e.depth && i < maxElementPathDepth; i++ {
+		part := e.names[i]
+		if e.indexes[i] >= 0 {
+			part += "[" + strconv.Itoa(e.indexes[i]) + "]"
+		}
+		parts = append(parts, part)
+	}
+	if child != "" {
+		parts = append(parts, child)
+	}
+	return strings.Join(parts, " > ")
+}
+
+// FindDuplicates reports every pair of positions (first, dup) in
+// a list of n values whose keys are equal, where first is the
+// earliest position with that key. Empty keys are ignored.
+//
+// Small lists are compared pairwise to avoid allocating,
+// larger lists are indexed with a map.
+func FindDuplicates(n int, key func(i int) string, report func(first, dup int)) {
+	if n <= 256 {
+		for i := 1; i < n; i++ {
+			k := key(i)
+			if k == "" {
+				continue
+			}
+			for j := 0; j < i; j++ {
+				if key(j) == k {
+					report(j, i)
+					break
+				}
+			}
+		}
+		return
+	}
+
+	seen := make(map[string]int, n)
+	for i := 0; i < n; i++ {
+		k := key(i)
+		if k == "" {
+			continue
+		}
+		if first, ok := seen[k]; ok {
+			report(first, i)
+			continue
+		}
+		seen[k] = i
+	}
+}
@@ -2,6 +2,7 @@ package shared
 
 import (
 	"strings"
+	"sync"
 
 	"github.com/mmcdole/gofeed/extensions"
 	"github.com/mmcdole/goxpp"
@@ -11,18 +12,20 @@ import (
 // XML element is an extension element (if it has a
 // non empty prefix)
 func IsExtension(p *xpp.XMLPullParser) bool {
-	space := strings.TrimSpace(p.Space)
-	prefix := PrefixForNamespace(space, p)
-	return !(prefix == "" || prefix == "rss" || prefix == "rdf" || prefix == "content")
+	return Prefixes(nil).IsExtension(p)
 }
 
 // ParseExtension parses the current element of the
 // XMLPullParser as an extension element and updates
 // the extension map
 func ParseExtension(fe ext.Extensions, p *xpp.XMLPullParser) (ext.Extensions, error) {
-	prefix := PrefixForNamespace(p.Space, p)
+	return parseExtension(fe, p, Limits{}, nil)
+}
 
-	result, err := parseExtensionElement(p)
+func parseExtension(fe ext.Extensions, p *xpp.XMLPullParser, l Limits, ps Prefixes) (ext.Extensions, error) {
+	prefix := ps.Prefix(p.Space, p)
+
+	result, err := parseExtensionElement(p, l)
 	if err != nil {
 		return nil, err
 	}
@@ -40,11 +43,15 @@ func ParseExtension(fe ext.Extensions, p *xpp.XMLPullParser) (ext.Extensions, er
 	return fe, nil
 }
 
-func parseExtensionElement(p *xpp.XMLPullParser) (e ext.Extension, err error) {
+func parseExtensionElement(p *xpp.XMLPullParser, l Limits) (e ext.Extension, err error) {
 	if err = p.Expect(xpp.StartTag, "*"); err != nil {
 		return e, err
 	}
 
+	if err = l.CheckDepth(p); err != nil {
+		return e, err
+	}
+
 	e.Name = p.Name
 	e.Children = map[string][]ext.Extension{}
 	e.Attrs = map[string]string{}
@@ -66,7 +73,7 @@ func parseExtensionElement(p *xpp.XMLPullParser) (e ext.Extension, err error) {
 		}
 
 		if tok == xpp.StartTag {
-			child, err := parseExtensionElement(p)
+			child, err := parseExtensionElement(p, l)
 			if err != nil {
 				return e, err
 			}
@@ -90,17 +97,44 @@ func parseExtensionElement(p *xpp.XMLPullParser) (e ext.Extension, err error) {
 	return e, nil
 }
 
+// DeclaredNamespaces returns a copy of the namespace
+// declarations in scope at the current element, mapping each
+// url to the prefix the feed declared it with ("" for the
+// default namespace). It returns nil if there are none.
+func DeclaredNamespaces(p *xpp.XMLPullParser) map[string]string {
+	if len(p.Spaces) == 0 {
+		return nil
+	}
+	spaces := make(map[string]string, len(p.Spaces))
+	for space, prefix := range p.Spaces {
+		spaces[space] = prefix
+	}
+	return spaces
+}
+
+// RegisterNamespace maps the namespace URL to a canonical
+// prefix. Registered prefixes take precedence over both the
+// built-in canonical prefixes and the prefixes used in feeds.
+func RegisterNamespace(space, prefix string) {
+	registeredNamespacesMu.Lock()
+	defer registeredNamespacesMu.Unlock()
+	registeredNamespaces[space] = prefix
+}
+
 func PrefixForNamespace(space string, p *xpp.XMLPullParser) string {
-	// First we check if the global namespace map
-	// contains an entry for this namespace/prefix.
-	// This way we can use the canonical prefix for this
-	// ns instead of the one defined in the feed.
-	if prefix, ok := canonicalNamespaces[space]; ok {
+	if prefix, ok := knownPrefix(space); ok {
 		return prefix
 	}
 
 	// Next we check if the feed itself defined this
 	// this namespace and return it if we have a result.
+	// The outermost declaration in scope wins so that
+	// re-declaring it on a child doesn't change it.
+	for _, spaces := range p.SpacesStack {
+		if prefix, ok := spaces[space]; ok {
+			return prefix
+		}
+	}
 	if prefix, ok := p.Spaces[space]; ok {
 		return prefix
 	}
@@ -111,6 +145,71 @@ func PrefixForNamespace(space string, p *xpp.XMLPullParser) string {
 	return space
 }
 
+// ResolvePrefix returns the prefix the extensions of the
+// namespace are keyed by in the Extensions of a parsed feed,
+// given the namespaces its root element declared.
+func ResolvePrefix(space string, declared map[string]string) string {
+	space = strings.TrimSpace(space)
+	if prefix, ok := knownPrefix(space); ok {
+		return prefix
+	}
+	if prefix, ok := declared[space]; ok {
+		return prefix
+	}
+	return space
+}
+
+// knownPrefix returns the prefix registered for the namespace
+// or else its canonical one.
+func knownPrefix(space string) (string, bool) {
+	// Namespaces registered by the caller win over
+	// everything else so they can pin a prefix.
+	registeredNamespacesMu.RLock()
+	prefix, ok := registeredNamespaces[space]
+	registeredNamespacesMu.RUnlock()
+	if ok {
+		return prefix, true
+	}
+
+	// Then we check if the global namespace map
+	// contains an entry for this namespace/prefix.
+	// This way we can use the canonical prefix for this
+	// ns instead of the one defined in the feed.
+	prefix, ok = canonicalNamespaces[space]
+	return prefix, ok
+}
+
+// Prefixes pins the prefix each namespace resolves to for
+// the duration of a single parse. A namespace declared under
+// different prefixes on sibling elements would otherwise have
+// its extensions split across those prefixes.
+type Prefixes map[string]string
+
+// Prefix is PrefixForNamespace, except that a namespace keeps
+// the prefix it resolved to the first time it was seen.
+func (ps Prefixes) Prefix(space string, p *xpp.XMLPullParser) string {
+	space = strings.TrimSpace(space)
+	if prefix, ok := ps[space]; ok {
+		return prefix
+	}
+	prefix := PrefixForNamespace(space, p)
+	if ps != nil {
+		ps[space] = prefix
+	}
+	return prefix
+}
+
+// IsExtension is IsExtension using the pinned prefixes.
+func (ps Prefixes) IsExtension(p *xpp.XMLPullParser) bool {
+	prefix := ps.Prefix(p.Space, p)
+	return !(prefix == "" || prefix == "rss" || prefix == "rdf" || prefix == "content")
+}
+
+var (
+	registeredNamespacesMu sync.RWMutex
+	registeredNamespaces   = map[string]string{}
+)
+
 // Namespaces taken from github.com/kurtmckee/feedparser
 // These are used for determining canonical name space prefixes
 // for many of the popular RSS/Atom extensions.
@@ -134,6 +233,7 @@ var canonicalNamespaces = map[string]string{
 	"http://purl.org/rss/1.0/modules/email/":                         "email",
 	"http://purl.org/rss/1.0/modules/event/":                         "ev",
 	"http://rssnamespace.org/feedburner/ext/1.0":                     "feedburner",
+	"http://purl.org/syndication/history/1.0":                        "fh",
 	"http://freshmeat.net/rss/fm/":                                   "fm",
 	"http://xmlns.com/foaf/0.1/":                                     "foaf",
 	"http://www.w3.org/2003/01/geo/wgs84_pos#":                       "geo",
@@ -162,6 +262,7 @@ var canonicalNamespaces = map[string]string{
 	"http://schemas.pocketsoap.com/rss/myDescModule/":                "szf",
 	"http://purl.org/rss/1.0/modules/taxonomy/":                      "taxo",
 	"http://purl.org/rss/1.0/modules/threading/":                     "thr",
+	"http://purl.org/syndication/thread/1.0":                         "thr",
 	"http://purl.org/rss/1.0/modules/textinput/":                     "ti",
 	"http://madskills.com/public/xml/rss/module/trackback/":          "trackback",
 	"http://wellformedweb.org/commentAPI/":                           "wfw",
@@ -169,5 +270,7 @@ var canonicalNamespaces = map[string]string{
 	"http://www.w3.org/1999/xhtml":                                   "xhtml",
 	"http://www.w3.org/1999/xlink":                                   "xlink",
 	"http://www.w3.org/XML/1998/namespace":                           "xml",
+	"urn:ietf:params:xml:ns:icalendar-2.0":                           "xcal",
+	"urn:ietf:params:xml:ns:xcal":                                    "xcal",
 	"http://podlove.org/simple-chapters":                             "psc",
 }
@@ -0,0 +1,80 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+
+	ext "github.com/mmcdole/gofeed/extensions"
+	xpp "github.com/mmcdole/goxpp"
+)
+
+// ErrLimitExceeded is wrapped by the error returned when a
+// feed goes over one of the configured parse limits.
+var ErrLimitExceeded = errors.New("parse limit exceeded")
+
+// Default parse limits used when a parser leaves them unset.
+// They are meant to be far beyond what any legitimate feed
+// needs while still bounding hostile input.
+const (
+	DefaultMaxItems        = 100000
+	DefaultMaxElementDepth = 256
+	DefaultMaxBytes        = 256 << 20
+)
+
+// Limits bounds the resources a single parse may consume.
+type Limits struct {
+	MaxItems        int
+	MaxElementDepth int
+	MaxBytes        int64
+}
+
+// NewLimits returns Limits for the given settings, replacing
+// each zero value with its default. Negative values disable
+// the corresponding limit.
+func NewLimits(maxItems, maxElementDepth int, maxBytes int64) Limits {
+	l := Limits{maxItems, maxElementDepth, maxBytes}
+	if l.MaxItems == 0 {
+		l.MaxItems = DefaultMaxItems
+	}
+	if l.MaxElementDepth == 0 {
+		l.MaxElementDepth = DefaultMaxElementDepth
+	}
+	if l.MaxBytes == 0 {
+		l.MaxBytes = DefaultMaxBytes
+	}
+	return l
+}
+
+// CheckItems returns an error once count items would go
+// over MaxItems.
+func (l Limits) CheckItems(count int) error {
+	if l.MaxItems > 0 && count > l.MaxItems {
+		return fmt.Errorf("%w: more than %d items", ErrLimitExceeded, l.MaxItems)
+	}
+	return nil
+}
+
+// CheckDepth returns an error if the parser is nested deeper
+// than MaxElementDepth.
+func (l Limits) CheckDepth(p *xpp.XMLPullParser) error {
+	if l.MaxElementDepth > 0 && p.Depth > l.MaxElementDepth {
+		return fmt.Errorf("%w: elements nested more than %d deep", ErrLimitExceeded, l.MaxElementDepth)
+	}
+	return nil
+}
+
+// NextTag is NextTag that also enforces MaxElementDepth.
+func (l Limits) NextTag(p *xpp.XMLPullParser) (event xpp.XMLEventType, err error) {
+	event, err = NextTag(p)
+	if err == nil && event == xpp.StartTag {
+		err = l.CheckDepth(p)
+	}
+	return
+}
+
+// ParseExtension is ParseExtension that also enforces
+// MaxElementDepth on the nested extension elements and
+// resolves the extension's prefix using ps.
+func (l Limits) ParseExtension(fe ext.Extensions, p *xpp.XMLPullParser, ps Prefixes) (ext.Extensions, error) {
+	return parseExtension(fe, p, l, ps)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"html"
 	"regexp"
+	"strconv"
 	"strings"
 
 	xpp "github.com/mmcdole/goxpp"
@@ -16,6 +17,7 @@ var (
 	nameEmailRgx = regexp.MustCompile(`^([^@]+)\s+\(([^@]+@[^)]+)\)$`)
 	nameOnlyRgx  = regexp.MustCompile(`^([^@()]+)$`)
 	emailOnlyRgx = regexp.MustCompile(`^([^@()]+@[^@()]+)$`)
+	mailboxRgx   = regexp.MustCompile(`^([^<>]*?)\s*<([^<>@\s]+@[^<>\s]+)>$`)
 
 	TruncatedEntity         = errors.New("truncated entity")
 	InvalidNumericReference = errors.New("invalid numeric reference")
@@ -44,11 +46,34 @@ func FindRoot(p *xpp.XMLPullParser) (event xpp.XMLEventType, err error) {
 	return
 }
 
+// AttributeNS returns the value of the attribute of the
+// current element with the given namespace and local name,
+// unlike Attribute which ignores the namespace.
+func AttributeNS(p *xpp.XMLPullParser, space, name string) string {
+	for _, attr := range p.Attrs {
+		if attr.Name.Space == space && attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
 // ParseText is a helper function for parsing the text
 // from the current element of the XMLPullParser.
 // This function can handle parsing naked XML text from
 // an element.
 func ParseText(p *xpp.XMLPullParser) (string, error) {
+	return ParseHTMLText(p, false)
+}
+
+// ParseHTMLText is ParseText for elements that carry HTML,
+// such as description and content:encoded. Text outside of
+// CDATA sections always gets exactly one level of entity
+// decoding. CDATA sections are returned verbatim unless
+// decodeCDATA is set, in which case they get that same single
+// level of decoding, so "<![CDATA[&lt;b&gt;]]>" yields "<b>"
+// just like "&lt;b&gt;" does.
+func ParseHTMLText(p *xpp.XMLPullParser, decodeCDATA bool) (string, error) {
 	var text struct {
 		Type     string `xml:"type,attr"`
 		InnerXML string `xml:",innerxml"`
@@ -63,7 +88,7 @@ func ParseText(p *xpp.XMLPullParser) (string, error) {
 	result = strings.TrimSpace(result)
 
 	if strings.Contains(result, CDATA_START) {
-		return StripCDATA(result), nil
+		return stripCDATA(result, decodeCDATA), nil
 	}
 
 	return DecodeEntities(result)
@@ -72,6 +97,10 @@ func ParseText(p *xpp.XMLPullParser) (string, error) {
 // StripCDATA removes CDATA tags from the string
 // content outside of CDATA tags is passed via DecodeEntities
 func StripCDATA(str string) string {
+	return stripCDATA(str, false)
+}
+
+func stripCDATA(str string, decodeCDATA bool) string {
 	buf := bytes.NewBuffer([]byte{})
 
 	curr := 0
@@ -79,26 +108,29 @@ func StripCDATA(str string) string {
 	for curr < len(str) {
 
 		start := indexAt(str, CDATA_START, curr)
-
 		if start == -1 {
-			dec, _ := DecodeEntities(str[curr:])
-			buf.Write([]byte(dec))
-			return buf.String()
+			break
 		}
 
 		end := indexAt(str, CDATA_END, start)
-
 		if end == -1 {
-			dec, _ := DecodeEntities(str[curr:])
-			buf.Write([]byte(dec))
-			return buf.String()
+			break
 		}
 
-		buf.Write([]byte(str[start+len(CDATA_START) : end]))
+		dec, _ := DecodeEntities(str[curr:start])
+		buf.WriteString(dec)
+
+		section := str[start+len(CDATA_START) : end]
+		if decodeCDATA {
+			section, _ = DecodeEntities(section)
+		}
+		buf.WriteString(section)
 
-		curr = curr + end + len(CDATA_END)
+		curr = end + len(CDATA_END)
 	}
 
+	dec, _ := DecodeEntities(str[curr:])
+	buf.WriteString(dec)
 	return buf.String()
 }
 
@@ -151,13 +183,18 @@ func DecodeEntities(str string) (string, error) {
 
 // ParseNameAddress parses name/email strings commonly
 // found in RSS feeds of the format "Example Name (example@site.com)"
-// and other variations of this format.
+// and other variations of this format, including the RFC 5322
+// mailbox form "Example Name <example@site.com>".
 func ParseNameAddress(nameAddressText string) (name string, address string) {
 	if nameAddressText == "" {
 		return
 	}
 
-	if emailNameRgx.MatchString(nameAddressText) {
+	if mailboxRgx.MatchString(nameAddressText) {
+		result := mailboxRgx.FindStringSubmatch(nameAddressText)
+		name = strings.Trim(strings.TrimSpace(result[1]), `"`)
+		address = result[2]
+	} else if emailNameRgx.MatchString(nameAddressText) {
 		result := emailNameRgx.FindStringSubmatch(nameAddressText)
 		address = result[1]
 		name = result[2]
@@ -175,6 +212,16 @@ func ParseNameAddress(nameAddressText string) (name string, address string) {
 	return
 }
 
+// ParseByteCount parses a length in bytes such as an
+// enclosure length. Non-numeric and negative values yield zero.
+func ParseByteCount(length string) int64 {
+	n, err := strconv.ParseInt(strings.TrimSpace(length), 10, 64)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
 func indexAt(str, substr string, start int) int {
 	idx := strings.Index(str[start:], substr)
 	if idx > -1 {
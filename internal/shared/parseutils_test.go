@@ -101,6 +101,8 @@ brackets or the greater-than sign using concatenated CDATA sections.
 		{`An example of escaped CENDs`, `An example of escaped CENDs`},
 		{`<![CDATA[This text contains a CEND ]]]]><![CDATA[>]]>`, `This text contains a CEND ]]>`},
 		{`<![CDATA[This text contains a CEND ]]]><![CDATA[]>]]>`, `This text contains a CEND ]]>`},
+		{`a &amp; <![CDATA[<b>]]> c`, `a & <b> c`},
+		{`x<![CDATA[1]]>y<![CDATA[2]]>z`, `x1y2z`},
 	}
 
 	for _, test := range tests {
@@ -108,3 +110,48 @@ brackets or the greater-than sign using concatenated CDATA sections.
 		assert.Equal(t, test.res, res)
 	}
 }
+
+func TestParseByteCount(t *testing.T) {
+	tests := []struct {
+		str string
+		res int64
+	}{
+		{"", 0},
+		{"0", 0},
+		{"123456", 123456},
+		{" 42 ", 42},
+		{"9876543210", 9876543210},
+		{"1,234", 0},
+		{"10MB", 0},
+		{"-5", 0},
+	}
+
+	for _, test := range tests {
+		res := ParseByteCount(test.str)
+		assert.Equal(t, test.res, res, "%q was parsed to %d instead of %d", test.str, res, test.res)
+	}
+}
+
+func TestParseNameAddress(t *testing.T) {
+	tests := []struct {
+		str     string
+		name    string
+		address string
+	}{
+		{"john@example.com (John Doe)", "John Doe", "john@example.com"},
+		{"John Doe (john@example.com)", "John Doe", "john@example.com"},
+		{"John Doe <john@example.com>", "John Doe", "john@example.com"},
+		{"John Doe<john@example.com>", "John Doe", "john@example.com"},
+		{`"Doe, John" <john@example.com>`, "Doe, John", "john@example.com"},
+		{"<john@example.com>", "", "john@example.com"},
+		{"john@example.com", "", "john@example.com"},
+		{"John Doe", "John Doe", ""},
+		{"", "", ""},
+	}
+
+	for _, test := range tests {
+		name, address := ParseNameAddress(test.str)
+		assert.Equal(t, test.name, name, test.str)
+		assert.Equal(t, test.address, address, test.str)
+	}
+}
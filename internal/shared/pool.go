@@ -0,0 +1,62 @@
+package shared
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"sync"
+
+	ext "github.com/mmcdole/gofeed/extensions"
+)
+
+// Buffers larger than this are dropped instead of pooled so a
+// single huge feed doesn't pin its memory for the lifetime of
+// the process.
+const maxPooledBufferSize = 1 << 20
+
+var (
+	bufferPool     = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+	readerPool     = sync.Pool{New: func() interface{} { return bufio.NewReader(nil) }}
+	extensionsPool = sync.Pool{New: func() interface{} { return ext.Extensions{} }}
+)
+
+// GetBuffer returns an empty buffer from the pool.
+func GetBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+// PutBuffer returns b to the pool. Nothing may hold on to
+// b or its contents afterwards.
+func PutBuffer(b *bytes.Buffer) {
+	if b.Cap() > maxPooledBufferSize {
+		return
+	}
+	b.Reset()
+	bufferPool.Put(b)
+}
+
+func getReader(r io.Reader) *bufio.Reader {
+	br := readerPool.Get().(*bufio.Reader)
+	br.Reset(r)
+	return br
+}
+
+func putReader(br *bufio.Reader) {
+	br.Reset(nil)
+	readerPool.Put(br)
+}
+
+// GetExtensions returns an empty extensions map from the pool
+// to collect the extensions of a single element into.
+func GetExtensions() ext.Extensions {
+	return extensionsPool.Get().(ext.Extensions)
+}
+
+// PutExtensions returns e to the pool if it is still empty.
+// Maps holding extensions are left alone since they end up in
+// the parsed feed.
+func PutExtensions(e ext.Extensions) {
+	if e != nil && len(e) == 0 {
+		extensionsPool.Put(e)
+	}
+}
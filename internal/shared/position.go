@@ -0,0 +1,97 @@
+package shared
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// PositionReader tracks how far into a document the XML
+// decoder has read. It implements io.ByteReader so the
+// decoder reads from it directly instead of wrapping it in a
+// buffer of its own, which keeps the offset exact as long as
+// the input doesn't need charset conversion.
+//
+// Reading past maxBytes fails with an error wrapping
+// ErrLimitExceeded instead of returning more data.
+type PositionReader struct {
+	r        *bufio.Reader
+	offset   int64
+	line     int
+	maxBytes int64
+}
+
+// NewPositionReader wraps r in a PositionReader. A maxBytes
+// of zero or less lets the whole input be read. Call Release
+// once done reading to let the buffer be reused.
+func NewPositionReader(r io.Reader, maxBytes int64) *PositionReader {
+	return &PositionReader{r: getReader(r), line: 1, maxBytes: maxBytes}
+}
+
+// Release returns the reader's buffer to the pool. The
+// PositionReader must not be read from afterwards, although
+// Position keeps working.
+func (pr *PositionReader) Release() {
+	if pr.r != nil {
+		putReader(pr.r)
+		pr.r = nil
+	}
+}
+
+func (pr *PositionReader) Read(b []byte) (int, error) {
+	if pr.maxBytes > 0 {
+		remaining := pr.maxBytes - pr.offset
+		if remaining <= 0 {
+			return 0, pr.limitError()
+		}
+		if int64(len(b)) > remaining {
+			b = b[:remaining]
+		}
+	}
+	n, err := pr.r.Read(b)
+	pr.advance(b[:n]...)
+	return n, err
+}
+
+func (pr *PositionReader) ReadByte() (byte, error) {
+	if pr.maxBytes > 0 && pr.offset >= pr.maxBytes {
+		return 0, pr.limitError()
+	}
+	c, err := pr.r.ReadByte()
+	if err == nil {
+		pr.advance(c)
+	}
+	return c, err
+}
+
+// limitError reports the limit as exceeded only if there
+// is input left beyond it.
+func (pr *PositionReader) limitError() error {
+	if _, err := pr.r.Peek(1); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: more than %d bytes", ErrLimitExceeded, pr.maxBytes)
+}
+
+func (pr *PositionReader) advance(b ...byte) {
+	pr.offset += int64(len(b))
+	for _, c := range b {
+		if c == '\n' {
+			pr.line++
+		}
+	}
+}
+
+// Position returns the byte offset and line number at which
+// err occurred. The line reported by an xml.SyntaxError is
+// preferred over the one counted by the reader.
+func (pr *PositionReader) Position(err error) (offset int64, line int) {
+	offset, line = pr.offset, pr.line
+	var syntaxErr *xml.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line = syntaxErr.Line
+	}
+	return
+}
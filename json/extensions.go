@@ -0,0 +1,70 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// Extensions are the custom extension objects of a feed or
+// item, keyed by their name including the leading underscore
+// (e.g. "_reader"). Values are kept as raw, compacted JSON for
+// the caller to decode.
+type Extensions map[string]json.RawMessage
+
+// parseExtensions fills in the Extensions of the feed and its
+// items from the document they were decoded from.
+func parseExtensions(data []byte, feed *Feed) error {
+	// Avoid decoding the document a second time for the
+	// common case of a feed without any extensions.
+	if !bytes.Contains(data, []byte(`"_`)) {
+		return nil
+	}
+
+	doc := struct {
+		Items []map[string]json.RawMessage `json:"items"`
+	}{}
+	if err := j.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	members := map[string]json.RawMessage{}
+	if err := j.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	var err error
+	if feed.Extensions, err = extensionMembers(members); err != nil {
+		return err
+	}
+	for i, item := range doc.Items {
+		if i >= len(feed.Items) {
+			break
+		}
+		if feed.Items[i] == nil {
+			continue
+		}
+		if feed.Items[i].Extensions, err = extensionMembers(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extensionMembers returns the members of a JSON object whose
+// names start with an underscore, or nil if there are none.
+func extensionMembers(members map[string]json.RawMessage) (extensions Extensions, err error) {
+	for name, value := range members {
+		if !strings.HasPrefix(name, "_") {
+			continue
+		}
+		compact := &bytes.Buffer{}
+		if err := json.Compact(compact, value); err != nil {
+			return nil, err
+		}
+		if extensions == nil {
+			extensions = Extensions{}
+		}
+		extensions[name] = compact.Bytes()
+	}
+	return extensions, nil
+}
@@ -18,7 +18,7 @@ type Feed struct {
 	Expired     bool    `json:"expired,omitempty"`       // expired (optional, boolean) says whether or not the feed is finished — that is, whether or not it will ever update again.
 	Items       []*Item `json:"items"`                   // items is an array, and is required
 	// TODO Hubs // hubs (very optional, array of objects) describes endpoints that can be used to subscribe to real-time notifications from the publisher of this feed. Each object has a type and url, both of which are required. See the section “Subscribing to Real-time Notifications” below for details.
-	// TODO Extensions
+	Extensions Extensions `json:"-"` // Custom extension objects, whose names start with an underscore
 
 	// Version 1.1
 	Authors  []*Author `json:"authors,omitempty"`
@@ -47,11 +47,13 @@ type Item struct {
 
 	Tags        []string       `json:"tags,omitempty"`        // tags (optional, array of strings) can have any plain text values you want. Tags tend to be just one word, but they may be anything.
 	Attachments *[]Attachments `json:"attachments,omitempty"` // attachments (optional, array) lists related resources. Podcasts, for instance, would include an attachment that’s an audio or video file. An individual item may have one or more attachments.
-	// TODO Extensions
+	Extensions  Extensions     `json:"-"`                     // Custom extension objects, whose names start with an underscore
 
 	// Version 1.1
 	Authors  []*Author `json:"authors,omitempty"`
 	Language string    `json:"language,omitempty"`
+
+	Index int `json:"-"` // Position in the document's items array, counting null entries the parser dropped
 }
 
 // Author defines the feed author structure. The author object has several members. These are all optional — but if you provide an author object, then at least one is required:
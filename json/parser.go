@@ -1,29 +1,84 @@
 package json
 
 import (
-	"bytes"
 	"io"
 
 	jsoniter "github.com/json-iterator/go"
+	"github.com/mmcdole/gofeed/internal/shared"
 )
 
 var (
 	j = jsoniter.ConfigCompatibleWithStandardLibrary
 )
 
+// ErrLimitExceeded is wrapped by the error returned when a
+// feed goes over one of the parser's limits.
+var ErrLimitExceeded = shared.ErrLimitExceeded
+
 // Parser is an JSON Feed Parser
-type Parser struct{}
+type Parser struct {
+	// Limits guarding against hostile feeds. Zero values
+	// fall back to generous defaults, negative values
+	// disable the limit.
+	MaxItems int   // Maximum number of items
+	MaxBytes int64 // Maximum size of the document
+}
 
 // Parse parses an json feed into an json.Feed
 func (ap *Parser) Parse(feed io.Reader) (*Feed, error) {
 	jsonFeed := &Feed{}
+	limits := shared.NewLimits(ap.MaxItems, 0, ap.MaxBytes)
 
-	buffer := new(bytes.Buffer)
-	buffer.ReadFrom(feed)
+	buffer := shared.GetBuffer()
+	defer shared.PutBuffer(buffer)
+	pr := shared.NewPositionReader(feed, limits.MaxBytes)
+	defer pr.Release()
+	if _, err := buffer.ReadFrom(pr); err != nil {
+		return nil, err
+	}
 
 	err := j.Unmarshal(buffer.Bytes(), jsonFeed)
 	if err != nil {
 		return nil, err
 	}
-	return jsonFeed, err
+
+	if err := limits.CheckItems(len(jsonFeed.Items)); err != nil {
+		return nil, err
+	}
+
+	if err := parseExtensions(buffer.Bytes(), jsonFeed); err != nil {
+		return nil, err
+	}
+	dropNulls(jsonFeed)
+	return jsonFeed, nil
+}
+
+// dropNulls removes the null entries of the feed's arrays of
+// objects, so users of the feed don't trip over nil pointers.
+// Items remember their original position in Index.
+func dropNulls(feed *Feed) {
+	feed.Authors = dropNullAuthors(feed.Authors)
+	items := feed.Items[:0]
+	for i, item := range feed.Items {
+		if item == nil {
+			continue
+		}
+		item.Index = i
+		item.Authors = dropNullAuthors(item.Authors)
+		items = append(items, item)
+	}
+	feed.Items = items
+}
+
+func dropNullAuthors(authors []*Author) []*Author {
+	if authors == nil {
+		return nil
+	}
+	result := authors[:0]
+	for _, author := range authors {
+		if author != nil {
+			result = append(result, author)
+		}
+	}
+	return result
 }
@@ -117,4 +117,39 @@ func TestParser_ParseInvalidAndStruct(t *testing.T) {
 	assert.Contains(t, actual.String(), "https://sample-json-feed.com/attachment")
 }
 
+func TestParser_Extensions(t *testing.T) {
+	feed := `{"version": "https://jsonfeed.org/version/1.1", "_ext": {"a": [1, 2]},
+"items": [{"id": "1", "_reader": { "read": true }, "title": "_not an extension"}, {"id": "2"}]}`
+
+	fp := &jsonParser.Parser{}
+	actual, err := fp.Parse(strings.NewReader(feed))
+
+	assert.Nil(t, err)
+	assert.Equal(t, jsonParser.Extensions{"_ext": json.RawMessage(`{"a":[1,2]}`)}, actual.Extensions)
+	assert.Equal(t, jsonParser.Extensions{"_reader": json.RawMessage(`{"read":true}`)}, actual.Items[0].Extensions)
+	assert.Nil(t, actual.Items[1].Extensions)
+
+	// Feeds without extensions also leave them nil
+	actual, err = fp.Parse(strings.NewReader(`{"version": "1.0", "items": [{"id": "1"}]}`))
+	assert.Nil(t, err)
+	assert.Nil(t, actual.Extensions)
+	assert.Nil(t, actual.Items[0].Extensions)
+}
+
+func TestParser_NullEntries(t *testing.T) {
+	feed := `{"version": "https://jsonfeed.org/version/1.1", "authors": [null, {"name": "Feed"}],
+"items": [null, {"id": "1", "_reader": {"read": true}, "authors": [null]}, null]}`
+
+	fp := &jsonParser.Parser{}
+	actual, err := fp.Parse(strings.NewReader(feed))
+
+	assert.Nil(t, err)
+	assert.Equal(t, []*jsonParser.Author{{Name: "Feed"}}, actual.Authors)
+	if assert.Len(t, actual.Items, 1) {
+		assert.Equal(t, "1", actual.Items[0].ID)
+		assert.Equal(t, jsonParser.Extensions{"_reader": json.RawMessage(`{"read":true}`)}, actual.Items[0].Extensions)
+		assert.Empty(t, actual.Items[0].Authors)
+	}
+}
+
 // TODO: Examples
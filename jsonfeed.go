@@ -0,0 +1,175 @@
+package gofeed
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/mmcdole/gofeed/json"
+	"golang.org/x/net/html"
+)
+
+// Version URL of the JSON Feed documents ConvertToJSONFeed
+// emits.
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+// ConvertToJSONFeed encodes feed, e.g. one parsed from RSS or
+// Atom, as an indented JSON Feed 1.1 document.
+//
+// Items get their GUID as id, falling back to their Link or
+// else a hash of their text, and their Content as content_html
+// or content_text depending on ContentType. Items without
+// Content use their Description instead, otherwise it becomes
+// the summary, stripped of its HTML. Dates are only written
+// when they could be parsed. Authors keep their name and URI,
+// or a mailto: url for their email. Fields JSON Feed has no
+// place for are left out.
+func ConvertToJSONFeed(feed *Feed) ([]byte, error) {
+	out := &json.Feed{
+		Version:     jsonFeedVersion,
+		Title:       feed.Title,
+		HomePageURL: feed.Link,
+		FeedURL:     feed.FeedLink,
+		Description: feed.Description,
+		NextURL:     relLink(feed.RelLinks, "next"),
+		Favicon:     feed.Icon,
+		Authors:     jsonFeedAuthors(feed.Authors),
+		Language:    feed.Language,
+	}
+	if feed.Image != nil {
+		out.Icon = feed.Image.URL
+	}
+	doc := &jsonFeedDocument{Feed: out, Items: []*jsonFeedEntry{}}
+	for _, item := range feed.Items {
+		if item != nil {
+			doc.Items = append(doc.Items, jsonFeedItem(item))
+		}
+	}
+	return jsoniter.ConfigCompatibleWithStandardLibrary.MarshalIndent(doc, "", "  ")
+}
+
+// jsonFeedDocument is a JSON Feed document whose items always
+// have a content field.
+type jsonFeedDocument struct {
+	*json.Feed
+	Items []*jsonFeedEntry `json:"items"`
+}
+
+// jsonFeedEntry is a JSON Feed item that writes its content
+// even when empty, as one of content_html and content_text
+// must be present.
+type jsonFeedEntry struct {
+	*json.Item
+	ContentHTML *string `json:"content_html,omitempty"`
+	ContentText *string `json:"content_text,omitempty"`
+}
+
+func jsonFeedItem(item *Item) *jsonFeedEntry {
+	out := &json.Item{
+		ID:            jsonFeedID(item),
+		URL:           item.Link,
+		Title:         item.Title,
+		DatePublished: jsonFeedDate(item.PublishedParsed),
+		DateModified:  jsonFeedDate(item.UpdatedParsed),
+		Authors:       jsonFeedAuthors(item.Authors),
+		Tags:          item.Categories,
+		Language:      item.Language,
+	}
+	entry := &jsonFeedEntry{Item: out}
+
+	content, contentType := item.Content, item.ContentType
+	if content == "" {
+		content, contentType = item.Description, item.DescriptionType
+	} else if item.DescriptionType == "text" {
+		out.Summary = item.Description
+	} else {
+		out.Summary = htmlText(item.Description)
+	}
+	if contentType == "text" {
+		entry.ContentText = &content
+	} else {
+		entry.ContentHTML = &content
+	}
+
+	if item.Image != nil {
+		out.Image = item.Image.URL
+	}
+	if item.BannerImage != nil {
+		out.BannerImage = item.BannerImage.URL
+	}
+
+	var attachments []json.Attachments
+	for _, enc := range item.Enclosures {
+		if enc != nil && enc.URL != "" {
+			attachments = append(attachments, json.Attachments{
+				URL:         enc.URL,
+				MimeType:    enc.Type,
+				SizeInBytes: enc.LengthInt,
+			})
+		}
+	}
+	if len(attachments) > 0 {
+		out.Attachments = &attachments
+	}
+	return entry
+}
+
+// jsonFeedID returns the id of item: its GUID or Link, or
+// for items that have neither a hash of their text, which
+// stays the same as long as the item doesn't change.
+func jsonFeedID(item *Item) string {
+	if item.GUID != "" {
+		return item.GUID
+	}
+	if item.Link != "" {
+		return item.Link
+	}
+	h := sha1.New()
+	for _, s := range []string{item.Title, item.Published, item.Description, item.Content} {
+		io.WriteString(h, s)
+		h.Write([]byte{0})
+	}
+	return fmt.Sprintf("urn:sha1:%x", h.Sum(nil))
+}
+
+// htmlText returns the text of an HTML fragment with its
+// whitespace collapsed.
+func htmlText(document string) string {
+	var text strings.Builder
+	z := html.NewTokenizer(strings.NewReader(document))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return strings.Join(strings.Fields(text.String()), " ")
+		case html.TextToken:
+			text.Write(z.Text())
+			text.WriteByte(' ')
+		}
+	}
+}
+
+func jsonFeedAuthors(persons []*Person) (authors []*json.Author) {
+	for _, p := range persons {
+		if p == nil {
+			continue
+		}
+		a := &json.Author{Name: p.Name, URL: p.URI}
+		if a.URL == "" && p.Email != "" {
+			a.URL = "mailto:" + p.Email
+		}
+		if a.Name != "" || a.URL != "" {
+			authors = append(authors, a)
+		}
+	}
+	return
+}
+
+func jsonFeedDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
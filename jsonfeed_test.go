@@ -0,0 +1,127 @@
+package gofeed_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertToJSONFeed(t *testing.T) {
+	feed := `<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+<channel>
+<title>Example Feed</title>
+<link>http://example.org/</link>
+<description>An example feed</description>
+<language>en-us</language>
+<atom:link rel="self" href="http://example.org/feed.rss"/>
+<atom:link rel="next" href="http://example.org/feed.rss?page=2"/>
+<item>
+<title>First</title>
+<link>http://example.org/1</link>
+<guid>urn:example:1</guid>
+<description>Summary of the first item</description>
+<content:encoded>&lt;p&gt;The first item&lt;/p&gt;</content:encoded>
+<author>jane@example.org (Jane Doe)</author>
+<category>news</category>
+<pubDate>Mon, 01 Jan 2024 10:00:00 GMT</pubDate>
+<enclosure url="http://example.org/1.mp3" length="1234" type="audio/mpeg"/>
+</item>
+<item>
+<link>http://example.org/2</link>
+<description>&lt;b&gt;Only&lt;/b&gt; a description</description>
+<pubDate>not a date</pubDate>
+</item>
+</channel>
+</rss>`
+
+	fp := gofeed.NewParser()
+	rss, err := fp.ParseString(feed)
+	assert.Nil(t, err)
+
+	data, err := gofeed.ConvertToJSONFeed(rss)
+	assert.Nil(t, err)
+
+	// Round trip the document through the JSON Feed parser
+	actual, err := fp.ParseBytes(data)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "json", actual.FeedType)
+	assert.Equal(t, "https://jsonfeed.org/version/1.1", actual.FeedVersion)
+	assert.Equal(t, "Example Feed", actual.Title)
+	assert.Equal(t, "http://example.org/", actual.Link)
+	assert.Equal(t, "http://example.org/feed.rss", actual.FeedLink)
+	assert.Equal(t, "An example feed", actual.Description)
+	assert.Equal(t, "en-us", actual.Language)
+	assert.Contains(t, actual.RelLinks, &gofeed.Link{Href: "http://example.org/feed.rss?page=2", Rel: "next"})
+
+	if assert.Len(t, actual.Items, 2) {
+		first := actual.Items[0]
+		assert.Equal(t, "urn:example:1", first.GUID)
+		assert.Equal(t, "http://example.org/1", first.Link)
+		assert.Equal(t, "First", first.Title)
+		assert.Equal(t, "<p>The first item</p>", first.Content)
+		assert.Equal(t, "html", first.ContentType)
+		assert.Equal(t, "Summary of the first item", first.Description)
+		assert.Equal(t, rss.Items[0].PublishedParsed.Unix(), first.PublishedParsed.Unix())
+		assert.Equal(t, []string{"news"}, first.Categories)
+		if assert.Len(t, first.Authors, 1) {
+			assert.Equal(t, "Jane Doe", first.Authors[0].Name)
+		}
+		assert.Equal(t, []*gofeed.Enclosure{
+			{URL: "http://example.org/1.mp3", Length: "1234", LengthInt: 1234, Type: "audio/mpeg"},
+		}, first.Enclosures)
+
+		// Without content the description is the content, the
+		// link stands in for the missing guid and the
+		// unparsable date is left out.
+		second := actual.Items[1]
+		assert.Equal(t, "http://example.org/2", second.GUID)
+		assert.Equal(t, "<b>Only</b> a description", second.Content)
+		assert.Equal(t, "html", second.ContentType)
+		assert.Empty(t, second.Description)
+		assert.Nil(t, second.PublishedParsed)
+	}
+}
+
+func TestConvertToJSONFeed_Fallbacks(t *testing.T) {
+	feed := &gofeed.Feed{Items: []*gofeed.Item{
+		{Title: "Untitled"},
+		{Title: "Untitled"},
+		{
+			Title:           "Summary",
+			Content:         "<p>Body</p>",
+			Description:     "<p>A <b>bold</b>\n  summary &amp; more</p>",
+			DescriptionType: "html",
+		},
+	}}
+
+	data, err := gofeed.ConvertToJSONFeed(feed)
+	assert.Nil(t, err)
+
+	var doc struct {
+		Items []map[string]string `json:"items"`
+	}
+	assert.Nil(t, json.Unmarshal(data, &doc))
+	if !assert.Len(t, doc.Items, 3) {
+		return
+	}
+
+	// Items with neither guid nor link get an id derived
+	// from their text, and an empty content field.
+	empty := doc.Items[0]
+	assert.True(t, strings.HasPrefix(empty["id"], "urn:sha1:"), empty["id"])
+	assert.Equal(t, empty["id"], doc.Items[1]["id"])
+	content, ok := empty["content_html"]
+	assert.True(t, ok, "content_html is missing")
+	assert.Empty(t, content)
+
+	// The summary is plain text
+	summary := doc.Items[2]
+	assert.NotEqual(t, empty["id"], summary["id"])
+	assert.Equal(t, "<p>Body</p>", summary["content_html"])
+	assert.Equal(t, "A bold summary & more", summary["summary"])
+}
@@ -0,0 +1,30 @@
+package gofeed
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// normalizeLanguages canonicalizes the language of the feed
+// and its items, see Parser.NormalizeLanguage.
+func normalizeLanguages(feed *Feed) {
+	feed.Language, feed.RawLanguage = normalizeLanguage(feed.Language)
+	for _, item := range feed.Items {
+		item.Language, item.RawLanguage = normalizeLanguage(item.Language)
+	}
+}
+
+// normalizeLanguage returns the canonical BCP 47 form of tag
+// along with the original value. Tags that can't be parsed
+// are returned as they are.
+func normalizeLanguage(tag string) (normalized, raw string) {
+	if tag == "" {
+		return "", ""
+	}
+	t, err := language.Parse(strings.Replace(strings.TrimSpace(tag), "_", "-", -1))
+	if err != nil {
+		return tag, tag
+	}
+	return t.String(), tag
+}
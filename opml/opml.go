@@ -0,0 +1,119 @@
+package opml
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/mmcdole/gofeed/internal/shared"
+)
+
+// OPML is an OPML document, the format aggregators use to
+// exchange lists of subscriptions.
+// http://opml.org/spec2.opml
+type OPML struct {
+	XMLName  xml.Name   `xml:"opml" json:"-"`
+	Version  string     `xml:"version,attr" json:"version"`
+	Head     *Head      `xml:"head" json:"head,omitempty"`
+	Outlines []*Outline `xml:"body>outline" json:"outlines,omitempty"`
+}
+
+func (o OPML) String() string {
+	json, _ := json.MarshalIndent(o, "", "    ")
+	return string(json)
+}
+
+// Feeds returns every outline of the document that
+// subscribes to a feed, in document order, flattening
+// the folders they are grouped in.
+func (o *OPML) Feeds() []*Outline {
+	return appendFeeds(nil, o.Outlines)
+}
+
+func appendFeeds(feeds []*Outline, outlines []*Outline) []*Outline {
+	for _, outline := range outlines {
+		if outline.XMLURL != "" {
+			feeds = append(feeds, outline)
+		}
+		feeds = appendFeeds(feeds, outline.Outlines)
+	}
+	return feeds
+}
+
+// Head is the metadata of an OPML document
+type Head struct {
+	Title        string `xml:"title,omitempty" json:"title,omitempty"`
+	DateCreated  string `xml:"dateCreated,omitempty" json:"dateCreated,omitempty"`
+	DateModified string `xml:"dateModified,omitempty" json:"dateModified,omitempty"`
+	OwnerName    string `xml:"ownerName,omitempty" json:"ownerName,omitempty"`
+	OwnerEmail   string `xml:"ownerEmail,omitempty" json:"ownerEmail,omitempty"`
+}
+
+// Outline is an outline element. Subscriptions carry the url
+// of the feed in XMLURL, outlines grouping subscriptions into
+// folders hold them in Outlines.
+type Outline struct {
+	Text     string     `xml:"text,attr" json:"text,omitempty"`
+	Title    string     `xml:"title,attr,omitempty" json:"title,omitempty"`
+	Type     string     `xml:"type,attr,omitempty" json:"type,omitempty"`
+	XMLURL   string     `xml:"xmlUrl,attr,omitempty" json:"xmlUrl,omitempty"`
+	HTMLURL  string     `xml:"htmlUrl,attr,omitempty" json:"htmlUrl,omitempty"`
+	Category string     `xml:"category,attr,omitempty" json:"category,omitempty"`
+	Outlines []*Outline `xml:"outline" json:"outlines,omitempty"`
+}
+
+// UnmarshalXML decodes an outline element, matching the
+// attribute names case-insensitively since many exporters
+// write xmlurl or XMLURL instead of xmlUrl.
+func (o *Outline) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return o.unmarshalXML(d, start, 1)
+}
+
+// unmarshalXML decodes an outline nested depth outlines deep,
+// failing past shared.DefaultMaxElementDepth so a hostile
+// document can't exhaust the stack.
+func (o *Outline) unmarshalXML(d *xml.Decoder, start xml.StartElement, depth int) error {
+	if depth > shared.DefaultMaxElementDepth {
+		return fmt.Errorf("%w: outlines nested more than %d deep", ErrLimitExceeded, shared.DefaultMaxElementDepth)
+	}
+	for _, attr := range start.Attr {
+		switch strings.ToLower(attr.Name.Local) {
+		case "text":
+			o.Text = attr.Value
+		case "title":
+			o.Title = attr.Value
+		case "type":
+			o.Type = attr.Value
+		case "xmlurl":
+			o.XMLURL = strings.TrimSpace(attr.Value)
+		case "htmlurl":
+			o.HTMLURL = strings.TrimSpace(attr.Value)
+		case "category":
+			o.Category = attr.Value
+		}
+	}
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local != "outline" {
+				if err := d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			child := &Outline{}
+			if err := child.unmarshalXML(d, t, depth+1); err != nil {
+				return err
+			}
+			o.Outlines = append(o.Outlines, child)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package opml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+
+	"github.com/mmcdole/gofeed/internal/shared"
+)
+
+// ErrLimitExceeded is wrapped by the error returned when a
+// document nests its outlines too deep.
+var ErrLimitExceeded = shared.ErrLimitExceeded
+
+// ParseOPML parses an OPML document, e.g. a list of
+// subscriptions exported from another aggregator.
+func ParseOPML(r io.Reader) (*OPML, error) {
+	d := xml.NewDecoder(r)
+	d.CharsetReader = shared.NewReaderLabel
+	d.Strict = false
+	d.Entity = xml.HTMLEntity
+
+	o := &OPML{}
+	if err := d.Decode(o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// MarshalOPML encodes o as an indented OPML document. The
+// version defaults to 2.0.
+func MarshalOPML(o *OPML) ([]byte, error) {
+	out := *o
+	if out.Version == "" {
+		out.Version = "2.0"
+	}
+
+	data, err := xml.MarshalIndent(&out, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	b := bytes.NewBufferString(xml.Header)
+	b.Write(data)
+	b.WriteByte('\n')
+	return b.Bytes(), nil
+}
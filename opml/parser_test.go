@@ -0,0 +1,92 @@
+package opml_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed/opml"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOPML(t *testing.T) {
+	f, _ := os.Open("../testdata/parser/opml/subscriptions.opml")
+	defer f.Close()
+
+	actual, err := opml.ParseOPML(f)
+
+	expected := &opml.OPML{
+		Version: "2.0",
+		Head: &opml.Head{
+			Title:       "Subscriptions",
+			DateCreated: "Mon, 01 Jan 2024 10:00:00 GMT",
+			OwnerName:   "Jane Doe",
+			OwnerEmail:  "jane@example.org",
+		},
+		Outlines: []*opml.Outline{
+			{Text: "News", Title: "News", Outlines: []*opml.Outline{
+				{Type: "rss", Text: "Example News", Title: "Example News", XMLURL: "http://example.org/news.xml", HTMLURL: "http://example.org/", Category: "/News,/World"},
+				{Text: "Tech", Outlines: []*opml.Outline{
+					{Type: "rss", Text: "Café Tech", XMLURL: "http://example.org/tech.xml", HTMLURL: "http://example.org/tech"},
+				}},
+			}},
+			{Type: "rss", Text: "Podcast", XMLURL: "http://example.org/podcast.xml"},
+			{Type: "link", Text: "Bookmark"},
+		},
+	}
+
+	if assert.Nil(t, err) {
+		actual.XMLName.Local = ""
+		assert.Equal(t, expected, actual)
+
+		feeds := []string{}
+		for _, outline := range actual.Feeds() {
+			feeds = append(feeds, outline.XMLURL)
+		}
+		assert.Equal(t, []string{
+			"http://example.org/news.xml",
+			"http://example.org/tech.xml",
+			"http://example.org/podcast.xml",
+		}, feeds)
+	}
+}
+
+func TestParseOPML_NotOPML(t *testing.T) {
+	_, err := opml.ParseOPML(strings.NewReader(`<rss version="2.0"><channel></channel></rss>`))
+	assert.NotNil(t, err)
+}
+
+func TestParseOPML_DeepNesting(t *testing.T) {
+	depth := 10000
+	doc := `<opml version="2.0"><body>` +
+		strings.Repeat(`<outline text="folder">`, depth) +
+		strings.Repeat(`</outline>`, depth) +
+		`</body></opml>`
+
+	_, err := opml.ParseOPML(strings.NewReader(doc))
+	assert.ErrorIs(t, err, opml.ErrLimitExceeded)
+}
+
+func TestMarshalOPML(t *testing.T) {
+	o := &opml.OPML{
+		Head: &opml.Head{Title: "Subscriptions"},
+		Outlines: []*opml.Outline{
+			{Text: "Folder", Outlines: []*opml.Outline{
+				{Type: "rss", Text: "A & B", XMLURL: "http://example.org/feed.xml?a=1&b=2"},
+			}},
+		},
+	}
+
+	data, err := opml.MarshalOPML(o)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(string(data), `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+`<opml version="2.0">`))
+	assert.Empty(t, o.Version)
+
+	actual, err := opml.ParseOPML(bytes.NewReader(data))
+	if assert.Nil(t, err) {
+		assert.Equal(t, "2.0", actual.Version)
+		assert.Equal(t, o.Head, actual.Head)
+		assert.Equal(t, o.Outlines, actual.Outlines)
+	}
+}
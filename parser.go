@@ -3,13 +3,16 @@ package gofeed
 import (
 	"bytes"
 	"context"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/mmcdole/gofeed/atom"
+	"github.com/mmcdole/gofeed/internal/shared"
 	"github.com/mmcdole/gofeed/json"
 	"github.com/mmcdole/gofeed/rss"
 )
@@ -18,6 +21,18 @@ import (
 // out the Feed format
 var ErrFeedTypeNotDetected = errors.New("Failed to detect feed type")
 
+// ErrLimitExceeded is wrapped by the error returned when a feed
+// goes over one of the parser's MaxItems, MaxElementDepth or
+// MaxBytes limits.
+var ErrLimitExceeded = shared.ErrLimitExceeded
+
+// ErrResponseTooLarge is wrapped by the error returned when a
+// response body is larger than the parser's MaxResponseBytes.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// Client used by ParseURL when the Parser doesn't set one.
+var defaultClient = &http.Client{}
+
 // HTTPError represents an HTTP error returned by a server.
 type HTTPError struct {
 	StatusCode int
@@ -28,9 +43,37 @@ func (err HTTPError) Error() string {
 	return fmt.Sprintf("http error: %s", err.Status)
 }
 
+// FetchInfo describes the HTTP response a feed was parsed
+// from, e.g. for caching and diagnostics. FinalURL is the url
+// the feed was served from after any redirects and discovery.
+type FetchInfo struct {
+	FinalURL     string
+	StatusCode   int
+	ContentType  string
+	ETag         string
+	LastModified string
+	Header       http.Header
+}
+
+func newFetchInfo(resp *http.Response) *FetchInfo {
+	return &FetchInfo{
+		FinalURL:     resp.Request.URL.String(),
+		StatusCode:   resp.StatusCode,
+		ContentType:  resp.Header.Get("Content-Type"),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Header:       resp.Header,
+	}
+}
+
 // Parser is a universal feed parser that detects
 // a given feed type, parsers it, and translates it
 // to the universal feed type.
+//
+// A Parser is safe for concurrent use by multiple
+// goroutines as long as its fields aren't modified while
+// it is in use, and should be reused rather than created
+// for every feed.
 type Parser struct {
 	AtomTranslator Translator
 	RSSTranslator  Translator
@@ -38,6 +81,63 @@ type Parser struct {
 	UserAgent      string
 	AuthConfig     *Auth
 	Client         *http.Client
+	// Limits guarding against hostile feeds, passed on to
+	// the feed specific parsers. Zero values fall back to
+	// generous defaults, negative values disable the limit.
+	MaxItems        int
+	MaxElementDepth int
+	MaxBytes        int64
+	// DecodeCDATA and DedupCategories are passed on to the
+	// RSS parser, see rss.Parser.
+	DecodeCDATA     bool
+	DedupCategories bool
+	// MaxRedirects caps the redirects ParseURL follows, 10
+	// when zero. Negative values don't follow any redirect.
+	// CheckRedirect is then called for every redirect, like
+	// http.Client.CheckRedirect, and can refuse it by
+	// returning an error. RejectInsecureRedirects refuses
+	// redirects from https to http. Credentials are only ever
+	// sent to the origin of the requested url.
+	MaxRedirects            int
+	CheckRedirect           func(req *http.Request, via []*http.Request) error
+	RejectInsecureRedirects bool
+	// MaxResponseBytes rejects responses fetched by ParseURL
+	// or passed to ParseResponse whose body is larger, before
+	// buffering it. A Content-Length over the limit fails
+	// without reading the body at all. A compressed body is
+	// limited again once decompressed. Zero means no limit
+	// besides MaxBytes.
+	MaxResponseBytes int64
+	// DisableExtensions is passed on to the RSS and Atom
+	// parsers, see rss.Parser.
+	DisableExtensions bool
+	// NormalizeLanguage rewrites the feed and item languages
+	// to canonical BCP 47 tags ("en_us" becomes "en-US"),
+	// keeping the original values in RawLanguage. Values that
+	// aren't valid language tags are left untouched.
+	NormalizeLanguage bool
+	// InferEnclosureTypes fills in the type of enclosures
+	// that don't have one from the extension of their url,
+	// marking them with TypeInferred.
+	InferEnclosureTypes bool
+	// Charset overrides the encoding the feed declares, for
+	// callers that know it from e.g. the Content-Type of the
+	// HTTP response. "utf-8" parses the feed as it is, any
+	// other label decodes it from that encoding first.
+	Charset string
+	// Strict returns an error for RSS and Atom feeds that
+	// aren't well-formed XML. By default they are parsed
+	// leniently instead and marked as Bozo, see Feed.Bozo.
+	Strict bool
+	// MetadataOnly parses the feed level fields only, skipping
+	// over the items and counting them in Feed.ItemCount
+	// instead. JSON feeds are still decoded in full.
+	MetadataOnly bool
+	// PreprocessHTML, when set, is applied to the description
+	// of the feed and to the description and content of every
+	// item, e.g. to sanitize them or resolve relative URLs in
+	// one place. Empty values are left alone.
+	PreprocessHTML func(html string) string
 	rp             *rss.Parser
 	ap             *atom.Parser
 	jp             *json.Parser
@@ -62,33 +162,109 @@ func NewParser() *Parser {
 	return &fp
 }
 
+// RegisterNamespace pins the prefix used for the given
+// namespace URL in the Extensions maps of every parsed feed.
+// It overrides the built-in canonical prefixes as well as
+// whatever prefix the feed itself declares.
+func RegisterNamespace(url, prefix string) {
+	shared.RegisterNamespace(url, prefix)
+}
+
+// RegisterTimezone sets the offset from UTC that dates naming
+// the given time zone abbreviation (e.g. "EST") are parsed
+// with. Common North American and European abbreviations are
+// built in; ambiguous ones resolve to US Central (CST),
+// Atlantic (AST), British Summer (BST) and India Standard
+// (IST) time unless registered otherwise.
+func RegisterTimezone(abbreviation string, offset time.Duration) {
+	shared.RegisterTimezone(abbreviation, int(offset/time.Second))
+}
+
 // Parse parses a RSS or Atom or JSON feed into
 // the universal gofeed.Feed.  It takes an
 // io.Reader which should return the xml/json content.
 func (f *Parser) Parse(feed io.Reader) (*Feed, error) {
-	// Wrap the feed io.Reader in a io.TeeReader
-	// so we can capture all the bytes read by the
-	// DetectFeedType function and construct a new
-	// reader with those bytes intact for when we
-	// attempt to parse the feeds.
-	var buf bytes.Buffer
-	tee := io.TeeReader(feed, &buf)
-	feedType := DetectFeedType(tee)
-
-	// Glue the read bytes from the detect function
-	// back into a new reader
-	r := io.MultiReader(&buf, feed)
-
-	switch feedType {
+	// Read the whole feed into a pooled buffer once so
+	// it can be both sniffed by the detection and then
+	// handed to the feed specific parser. Reading stops at
+	// MaxBytes so an oversized feed is never buffered whole.
+	limits := shared.NewLimits(f.MaxItems, f.MaxElementDepth, f.MaxBytes)
+	pr := shared.NewPositionReader(feed, limits.MaxBytes)
+	defer pr.Release()
+	buf := shared.GetBuffer()
+	defer shared.PutBuffer(buf)
+	if _, err := buf.ReadFrom(pr); err != nil {
+		return nil, err
+	}
+	return f.ParseBytes(buf.Bytes())
+}
+
+// ParseBytes parses a RSS or Atom or JSON feed held in
+// memory into the universal gofeed.Feed. Unlike Parse and
+// ParseString it reads the slice in place without copying
+// it first. The parsed feed doesn't reference feed, so the
+// slice may be reused once ParseBytes returns.
+func (f *Parser) ParseBytes(feed []byte) (*Feed, error) {
+	if f.Charset != "" {
+		e, err := shared.LookupCharset(f.Charset)
+		if err != nil {
+			return nil, err
+		}
+		if e != nil {
+			if feed, err = e.NewDecoder().Bytes(feed); err != nil {
+				return nil, err
+			}
+		}
+	}
+	var result *Feed
+	var err error
+	switch detectFeedType(feed) {
 	case FeedTypeAtom:
-		return f.parseAtomFeed(r)
+		result, err = f.parseXMLFeed(feed, f.parseAtomFeed)
 	case FeedTypeRSS:
-		return f.parseRSSFeed(r)
+		result, err = f.parseXMLFeed(feed, f.parseRSSFeed)
 	case FeedTypeJSON:
-		return f.parseJSONFeed(r)
+		result, err = f.parseJSONFeed(bytes.NewReader(feed))
+	default:
+		return nil, ErrFeedTypeNotDetected
+	}
+
+	if err == nil {
+		f.postProcess(result)
 	}
+	return result, err
+}
 
-	return nil, ErrFeedTypeNotDetected
+// postProcess applies the options that work on the translated
+// feed rather than on the source document.
+func (f *Parser) postProcess(result *Feed) {
+	if !f.Strict && !result.Bozo && len(result.Warnings) > 0 {
+		result.Bozo, result.BozoErr = true, result.Warnings[0]
+	}
+	if f.NormalizeLanguage {
+		normalizeLanguages(result)
+	}
+	if f.InferEnclosureTypes {
+		inferEnclosureTypes(result)
+	}
+	if f.PreprocessHTML != nil {
+		preprocessHTML(result, f.PreprocessHTML)
+	}
+}
+
+// preprocessHTML applies fn to the description and content
+// fields of feed, see Parser.PreprocessHTML.
+func preprocessHTML(feed *Feed, fn func(string) string) {
+	apply := func(s *string) {
+		if *s != "" {
+			*s = fn(*s)
+		}
+	}
+	apply(&feed.Description)
+	for _, item := range feed.Items {
+		apply(&item.Description)
+		apply(&item.Content)
+	}
 }
 
 // ParseURL fetches the contents of a given url and
@@ -103,12 +279,91 @@ func (f *Parser) ParseURL(feedURL string) (feed *Feed, err error) {
 // to use the BasicAuth during the HTTP call.
 // It will be automatically added to the header of the request
 // Request could be canceled or timeout via given context
+//
+// When the url points at an HTML page rather than a feed, the
+// feed the page advertises is fetched and parsed instead (see
+// ParseURLWithDiscovery).
 func (f *Parser) ParseURLWithContext(feedURL string, ctx context.Context) (feed *Feed, err error) {
+	feed, _, err = f.ParseURLWithDiscovery(feedURL, ctx)
+	return feed, err
+}
+
+// ParseURLWithDiscovery is like ParseURLWithContext but also
+// returns the URL the feed was parsed from. If the fetched
+// document is an HTML page, its <link rel="alternate"> tags
+// are used to discover the feed: Atom is preferred over RSS,
+// which is preferred over JSON. A *DiscoveryError listing the
+// candidates is returned when the page links several feeds of
+// the preferred type.
+func (f *Parser) ParseURLWithDiscovery(feedURL string, ctx context.Context) (feed *Feed, resolvedURL string, err error) {
+	feed, resp, err := f.parseURL(feedURL, ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	return feed, resp.Request.URL.String(), nil
+}
+
+// ParseURLWithInfo is like ParseURLWithContext but also
+// returns the final url, status code and headers of the
+// response the feed was parsed from.
+func (f *Parser) ParseURLWithInfo(feedURL string, ctx context.Context) (feed *Feed, info *FetchInfo, err error) {
+	feed, resp, err := f.parseURL(feedURL, ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return feed, newFetchInfo(resp), nil
+}
+
+// ParseResponse parses the body of a response the caller
+// fetched itself, for full control over the HTTP request.
+// Responses with a non-2xx status return an HTTPError. The
+// caller remains responsible for closing the body.
+func (f *Parser) ParseResponse(resp *http.Response) (*Feed, error) {
+	body, err := f.readResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	return f.ParseBytes(body)
+}
+
+// parseURL fetches and parses the feed at feedURL, following
+// the feed an HTML page advertises. It returns the response
+// the feed was eventually parsed from, with its body closed.
+func (f *Parser) parseURL(feedURL string, ctx context.Context) (feed *Feed, resp *http.Response, err error) {
+	body, resp, err := f.fetch(feedURL, ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if detectFeedType(body) == FeedTypeUnknown {
+		discovered, err := discoverFeedURL(body, resp.Request.URL)
+		if err != nil {
+			return nil, nil, err
+		}
+		if discovered != "" {
+			body, resp, err = f.fetch(discovered, ctx)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	feed, err = f.ParseBytes(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return feed, resp, nil
+}
+
+// fetch returns the body of the given url along with the
+// response it was read from. resp.Request.URL is the url it
+// was eventually served from after any redirects.
+func (f *Parser) fetch(feedURL string, ctx context.Context) (body []byte, resp *http.Response, err error) {
 	client := f.httpClient()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	req.Header.Set("User-Agent", f.UserAgent)
 
@@ -116,21 +371,32 @@ func (f *Parser) ParseURLWithContext(feedURL string, ctx context.Context) (feed
 		req.SetBasicAuth(f.AuthConfig.Username, f.AuthConfig.Password)
 	}
 
-	resp, err := client.Do(req)
+	r, err := client.Do(req)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if resp != nil {
+	if r != nil {
 		defer func() {
-			ce := resp.Body.Close()
+			ce := r.Body.Close()
 			if ce != nil {
 				err = ce
 			}
 		}()
 	}
 
+	body, err = f.readResponse(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return body, r, nil
+}
+
+// readResponse reads the body of resp within the parser's
+// MaxBytes limit, returning an HTTPError for non-2xx statuses.
+// gzip and deflate compressed bodies are decompressed.
+func (f *Parser) readResponse(resp *http.Response) ([]byte, error) {
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return nil, HTTPError{
 			StatusCode: resp.StatusCode,
@@ -138,7 +404,36 @@ func (f *Parser) ParseURLWithContext(feedURL string, ctx context.Context) (feed
 		}
 	}
 
-	return f.Parse(resp.Body)
+	var body io.Reader = resp.Body
+	if f.MaxResponseBytes > 0 {
+		if resp.ContentLength > f.MaxResponseBytes {
+			return nil, f.responseTooLarge()
+		}
+		body = f.limitResponse(body)
+	}
+
+	// The limit applies to the decompressed body as well, so
+	// a small compressed response can't expand without bound.
+	body, err := decompressBody(body, resp.Header.Get("Content-Encoding"))
+	if err != nil {
+		return nil, err
+	}
+	if f.MaxResponseBytes > 0 {
+		body = f.limitResponse(body)
+	}
+
+	limits := shared.NewLimits(f.MaxItems, f.MaxElementDepth, f.MaxBytes)
+	pr := shared.NewPositionReader(body, limits.MaxBytes)
+	defer pr.Release()
+	return io.ReadAll(pr)
+}
+
+func (f *Parser) limitResponse(body io.Reader) io.Reader {
+	return &limitedReader{r: body, n: f.MaxResponseBytes, err: f.responseTooLarge()}
+}
+
+func (f *Parser) responseTooLarge() error {
+	return fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, f.MaxResponseBytes)
 }
 
 // ParseString parses a feed XML string and into the
@@ -147,59 +442,195 @@ func (f *Parser) ParseString(feed string) (*Feed, error) {
 	return f.Parse(strings.NewReader(feed))
 }
 
-func (f *Parser) parseAtomFeed(feed io.Reader) (*Feed, error) {
-	af, err := f.ap.Parse(feed)
+// ParseWithContext is like Parse but gives up once ctx is
+// done, returning ctx.Err(). Reading from feed stops at that
+// point, though a Read already in progress is left to finish
+// in the background.
+func (f *Parser) ParseWithContext(feed io.Reader, ctx context.Context) (*Feed, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	type parsed struct {
+		feed *Feed
+		err  error
+	}
+	done := make(chan parsed, 1)
+	go func() {
+		result, err := f.Parse(&contextReader{ctx: ctx, r: feed})
+		done <- parsed{result, err}
+	}()
+
+	select {
+	case p := <-done:
+		return p.feed, p.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// ParseTimeout is like Parse but gives up after d, returning
+// an error that matches context.DeadlineExceeded.
+func (f *Parser) ParseTimeout(feed io.Reader, d time.Duration) (*Feed, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return f.ParseWithContext(feed, ctx)
+}
+
+// contextReader fails reads once its context is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr *contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
+
+// xmlCharset is the Charset for the feed specific parsers.
+// ParseBytes has already decoded the feed if Charset is set.
+func (f *Parser) xmlCharset() string {
+	if f.Charset != "" {
+		return "utf-8"
+	}
+	return ""
+}
+
+// parseXMLFeed parses an RSS or Atom feed as well-formed XML
+// first. Unless Strict is set, a feed that isn't well-formed
+// is then parsed again leniently and marked as Bozo.
+func (f *Parser) parseXMLFeed(feed []byte, parse func(io.Reader, bool) (*Feed, error)) (*Feed, error) {
+	result, err := parse(bytes.NewReader(feed), true)
+	var serr *xml.SyntaxError
+	if err == nil || f.Strict || !errors.As(err, &serr) {
+		return result, err
+	}
+
+	result, lenientErr := parse(bytes.NewReader(feed), false)
+	if lenientErr != nil {
+		return nil, lenientErr
+	}
+	result.Bozo, result.BozoErr = true, err
+	return result, nil
+}
+
+// atomParser returns the Atom parser configured with the
+// options of f.
+func (f *Parser) atomParser(strict bool) atom.Parser {
+	ap := atom.Parser{}
+	if f.ap != nil {
+		ap = *f.ap
+	}
+	ap.MaxItems, ap.MaxElementDepth, ap.MaxBytes = f.MaxItems, f.MaxElementDepth, f.MaxBytes
+	ap.DisableExtensions = f.DisableExtensions
+	ap.Charset = f.xmlCharset()
+	ap.Strict = strict
+	ap.MetadataOnly = f.MetadataOnly
+	return ap
+}
+
+func (f *Parser) parseAtomFeed(feed io.Reader, strict bool) (*Feed, error) {
+	ap := f.atomParser(strict)
+	af, err := ap.Parse(feed)
 	if err != nil {
+		var perr *atom.ParseError
+		if errors.As(err, &perr) {
+			return nil, &ParseError{Offset: perr.Offset, Line: perr.Line, Path: perr.Path, Err: perr.Err}
+		}
 		return nil, err
 	}
-	return f.atomTrans().Translate(af)
+	return translate(f.atomTrans(), af)
+}
+
+// rssParser returns the RSS parser configured with the
+// options of f.
+func (f *Parser) rssParser(strict bool) rss.Parser {
+	rp := rss.Parser{}
+	if f.rp != nil {
+		rp = *f.rp
+	}
+	rp.MaxItems, rp.MaxElementDepth, rp.MaxBytes = f.MaxItems, f.MaxElementDepth, f.MaxBytes
+	rp.DecodeCDATA, rp.DedupCategories = f.DecodeCDATA, f.DedupCategories
+	rp.DisableExtensions = f.DisableExtensions
+	rp.Charset = f.xmlCharset()
+	rp.Strict = strict
+	rp.MetadataOnly = f.MetadataOnly
+	return rp
 }
 
-func (f *Parser) parseRSSFeed(feed io.Reader) (*Feed, error) {
-	rf, err := f.rp.Parse(feed)
+func (f *Parser) parseRSSFeed(feed io.Reader, strict bool) (*Feed, error) {
+	rp := f.rssParser(strict)
+	rf, err := rp.Parse(feed)
 	if err != nil {
+		var perr *rss.ParseError
+		if errors.As(err, &perr) {
+			return nil, &ParseError{Offset: perr.Offset, Line: perr.Line, Path: perr.Path, Err: perr.Err}
+		}
 		return nil, err
 	}
 
-	return f.rssTrans().Translate(rf)
+	return translate(f.rssTrans(), rf)
 }
 
 func (f *Parser) parseJSONFeed(feed io.Reader) (*Feed, error) {
-	jf, err := f.jp.Parse(feed)
+	jp := json.Parser{}
+	if f.jp != nil {
+		jp = *f.jp
+	}
+	jp.MaxItems, jp.MaxBytes = f.MaxItems, f.MaxBytes
+	jf, err := jp.Parse(feed)
 	if err != nil {
 		return nil, err
 	}
-	return f.jsonTrans().Translate(jf)
+	result, err := translate(f.jsonTrans(), jf)
+	if err == nil && f.MetadataOnly {
+		result.ItemCount = len(result.Items)
+		result.Items = []*Item{}
+	}
+	return result, err
+}
+
+// translate runs a translator, keeping the feed and adding
+// the warnings of a *TranslationError to it instead of failing.
+func translate(t Translator, feed interface{}) (*Feed, error) {
+	result, err := t.Translate(feed)
+	var terr *TranslationError
+	if result != nil && errors.As(err, &terr) {
+		result.Warnings = append(result.Warnings, terr.Warnings...)
+		return result, nil
+	}
+	return result, err
 }
 
 func (f *Parser) atomTrans() Translator {
 	if f.AtomTranslator != nil {
 		return f.AtomTranslator
 	}
-	f.AtomTranslator = &DefaultAtomTranslator{}
-	return f.AtomTranslator
+	return &DefaultAtomTranslator{}
 }
 
 func (f *Parser) rssTrans() Translator {
 	if f.RSSTranslator != nil {
 		return f.RSSTranslator
 	}
-	f.RSSTranslator = &DefaultRSSTranslator{}
-	return f.RSSTranslator
+	return &DefaultRSSTranslator{}
 }
 
 func (f *Parser) jsonTrans() Translator {
 	if f.JSONTranslator != nil {
 		return f.JSONTranslator
 	}
-	f.JSONTranslator = &DefaultJSONTranslator{}
-	return f.JSONTranslator
+	return &DefaultJSONTranslator{}
 }
 
 func (f *Parser) httpClient() *http.Client {
+	client := *defaultClient
 	if f.Client != nil {
-		return f.Client
+		client = *f.Client
 	}
-	f.Client = &http.Client{}
-	return f.Client
+	client.CheckRedirect = f.checkRedirect(client.CheckRedirect)
+	return &client
 }
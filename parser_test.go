@@ -2,13 +2,20 @@ package gofeed_test
 
 import (
 	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"testing"
@@ -104,6 +111,308 @@ func TestParser_ParseString(t *testing.T) {
 	}
 }
 
+func TestParser_ParseBytes(t *testing.T) {
+	var feedTests = []struct {
+		file      string
+		feedType  string
+		feedTitle string
+		hasError  bool
+	}{
+		{"atom10_feed.xml", "atom", "Feed Title", false},
+		{"rss_feed_bom.xml", "rss", "Feed Title", false},
+		{"json10_feed.json", "json", "title", false},
+		{"unknown_feed.xml", "", "", true},
+	}
+
+	for _, test := range feedTests {
+		fmt.Printf("Testing %s... ", test.file)
+
+		path := fmt.Sprintf("testdata/parser/universal/%s", test.file)
+		f, _ := os.ReadFile(path)
+
+		fp := gofeed.NewParser()
+		feed, err := fp.ParseBytes(f)
+
+		if test.hasError {
+			assert.NotNil(t, err)
+			assert.Nil(t, feed)
+		} else {
+			assert.Nil(t, err)
+			assert.Equal(t, test.feedType, feed.FeedType)
+			assert.Equal(t, test.feedTitle, feed.Title)
+		}
+	}
+}
+
+func TestParser_NormalizeLanguage(t *testing.T) {
+	var languageTests = []struct {
+		language string
+		expected string
+	}{
+		{"en-US", "en-US"},
+		{"en_us", "en-US"},
+		{"EN", "en"},
+		{"zh-hant-tw", "zh-Hant-TW"},
+		{"english", "english"},
+		{"", ""},
+	}
+
+	for _, test := range languageTests {
+		feedData := fmt.Sprintf(`<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<channel><language>%s</language><item><dc:language>%s</dc:language></item></channel>
+</rss>`, test.language, test.language)
+
+		fp := gofeed.NewParser()
+		fp.NormalizeLanguage = true
+		feed, err := fp.ParseString(feedData)
+
+		assert.Nil(t, err)
+		assert.Equal(t, test.expected, feed.Language)
+		assert.Equal(t, test.language, feed.RawLanguage)
+		assert.Equal(t, test.expected, feed.Items[0].Language)
+		assert.Equal(t, test.language, feed.Items[0].RawLanguage)
+	}
+
+	// Languages are left alone unless asked for
+	feed, _ := gofeed.NewParser().ParseString(`<rss version="2.0"><channel><language>en_us</language></channel></rss>`)
+	assert.Equal(t, "en_us", feed.Language)
+	assert.Empty(t, feed.RawLanguage)
+}
+
+func TestParser_InferEnclosureTypes(t *testing.T) {
+	var enclosureTests = []struct {
+		url      string
+		expected string
+	}{
+		{"http://example.org/episode.mp3", "audio/mpeg"},
+		{"http://example.org/episode.M4A?download=1", "audio/mp4"},
+		{"http://example.org/video.mp4#t=10", "video/mp4"},
+		{"http://example.org/paper.pdf", "application/pdf"},
+		{"http://example.org/cover.jpg", "image/jpeg"},
+		{"http://example.org/notes.html", "text/html"},
+		{"http://example.org/download", ""},
+		{"http://example.org/file.unknownext", ""},
+	}
+
+	for _, test := range enclosureTests {
+		feedData := fmt.Sprintf(`<rss version="2.0"><channel><item>
+<enclosure url="%s" length="1"/><enclosure url="%s" length="1" type="audio/x-custom"/>
+</item></channel></rss>`, test.url, test.url)
+
+		fp := gofeed.NewParser()
+		fp.InferEnclosureTypes = true
+		feed, err := fp.ParseString(feedData)
+
+		assert.Nil(t, err)
+		enclosures := feed.Items[0].Enclosures
+		assert.Equal(t, test.expected, enclosures[0].Type, test.url)
+		assert.Equal(t, test.expected != "", enclosures[0].TypeInferred, test.url)
+		assert.Equal(t, "audio/x-custom", enclosures[1].Type, test.url)
+		assert.False(t, enclosures[1].TypeInferred, test.url)
+	}
+
+	// Types are left empty unless asked for
+	feed, _ := gofeed.NewParser().ParseString(`<rss version="2.0"><channel><item><enclosure url="http://example.org/a.mp3"/></item></channel></rss>`)
+	assert.Empty(t, feed.Items[0].Enclosures[0].Type)
+}
+
+func TestParser_Charset(t *testing.T) {
+	latin1 := "<?xml version=\"1.0\" encoding=\"utf-8\"?><rss version=\"2.0\"><channel><title>Caf\xe9</title></channel></rss>"
+	utf8 := `<?xml version="1.0" encoding="iso-8859-1"?><rss version="2.0"><channel><title>Café</title></channel></rss>`
+	atom := `<?xml version="1.0" encoding="x-unknown"?><feed xmlns="http://www.w3.org/2005/Atom"><title>Café</title></feed>`
+
+	var charsetTests = []struct {
+		charset  string
+		feed     string
+		expected string
+	}{
+		{"", utf8, "CafÃ©"},
+		{"utf-8", utf8, "Café"},
+		{"UTF8", utf8, "Café"},
+		{"iso-8859-1", latin1, "Café"},
+		{"utf-8", atom, "Café"},
+	}
+
+	for _, test := range charsetTests {
+		fp := gofeed.NewParser()
+		fp.Charset = test.charset
+		feed, err := fp.ParseString(test.feed)
+
+		if assert.Nil(t, err, test.charset) {
+			assert.Equal(t, test.expected, feed.Title, test.charset)
+		}
+	}
+
+	fp := gofeed.NewParser()
+	fp.Charset = "x-unknown"
+	_, err := fp.ParseString(utf8)
+	assert.EqualError(t, err, `unsupported charset "x-unknown"`)
+}
+
+func TestParser_Bozo(t *testing.T) {
+	wellFormed := `<rss version="2.0"><channel><title>Title</title></channel></rss>`
+	malformed := `<rss version="2.0"><channel><title>A&nbsp;B</title><link>http://example.org/?a=1&b=2</link></channel></rss>`
+	warning := `<rss version="2.0"><channel><pubDate>sometime</pubDate></channel></rss>`
+
+	fp := gofeed.NewParser()
+	feed, err := fp.ParseString(wellFormed)
+	assert.Nil(t, err)
+	assert.False(t, feed.Bozo)
+	assert.Nil(t, feed.BozoErr)
+
+	feed, err = fp.ParseString(malformed)
+	assert.Nil(t, err)
+	assert.True(t, feed.Bozo)
+	var serr *xml.SyntaxError
+	assert.ErrorAs(t, feed.BozoErr, &serr)
+	assert.Equal(t, "A\u00a0B", feed.Title)
+	assert.Equal(t, "http://example.org/?a=1&b=2", feed.Link)
+
+	feed, err = fp.ParseString(warning)
+	assert.Nil(t, err)
+	assert.True(t, feed.Bozo)
+	assert.Equal(t, feed.Warnings[0], feed.BozoErr)
+
+	fp.Strict = true
+	_, err = fp.ParseString(malformed)
+	assert.ErrorAs(t, err, &serr)
+
+	feed, err = fp.ParseString(warning)
+	assert.Nil(t, err)
+	assert.False(t, feed.Bozo)
+	assert.Nil(t, feed.BozoErr)
+	assert.Len(t, feed.Warnings, 1)
+}
+
+func TestParser_TranslationWarnings(t *testing.T) {
+	feed := `{"version": "https://jsonfeed.org/version/1.1", "title": "Feed",
+"items": [{"id": "1", "date_published": "last tuesday"}, {"id": "2", "date_published": "2024-02-25T10:00:00Z"}]}`
+
+	fp := gofeed.NewParser()
+	actual, err := fp.ParseString(feed)
+
+	assert.Nil(t, err)
+	assert.Len(t, actual.Items, 2)
+	assert.Equal(t, []gofeed.ParseWarning{
+		{Path: "items[0] > published", Message: `unparseable date "last tuesday"`},
+	}, actual.Warnings)
+	assert.True(t, actual.Bozo)
+}
+
+func TestParser_ParseTimeout(t *testing.T) {
+	fp := gofeed.NewParser()
+	feed, err := fp.ParseTimeout(strings.NewReader(`<rss version="2.0"><channel><title>Title</title></channel></rss>`), time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, "Title", feed.Title)
+
+	// A reader that never delivers the rest of the feed
+	r, w := io.Pipe()
+	defer w.Close()
+	go w.Write([]byte(`<rss version="2.0"><channel>`))
+
+	feed, err = fp.ParseTimeout(r, 50*time.Millisecond)
+	assert.Nil(t, feed)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestParser_MetadataOnly(t *testing.T) {
+	var metadataTests = []struct {
+		name string
+		feed string
+	}{
+		{"rss", `<rss version="2.0"><channel><title>Feed</title>` + strings.Repeat(`<item><title>Item</title></item>`, 3) + `</channel></rss>`},
+		{"rdf", `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/"><channel><title>Feed</title></channel>` +
+			strings.Repeat(`<item><title>Item</title></item>`, 3) + `</rdf:RDF>`},
+		{"atom", `<feed xmlns="http://www.w3.org/2005/Atom"><title>Feed</title>` + strings.Repeat(`<entry><title>Item</title></entry>`, 3) + `</feed>`},
+		{"json", `{"version": "https://jsonfeed.org/version/1.1", "title": "Feed", "items": [{"id": "1"}, {"id": "2"}, {"id": "3"}]}`},
+	}
+
+	fp := gofeed.NewParser()
+	fp.MetadataOnly = true
+	for _, test := range metadataTests {
+		actual, err := fp.ParseString(test.feed)
+		if assert.NoError(t, err, test.name) {
+			assert.Equal(t, "Feed", actual.Title, test.name)
+			assert.Equal(t, 3, actual.ItemCount, test.name)
+			assert.Empty(t, actual.Items, test.name)
+		}
+	}
+}
+
+func TestParser_PreprocessHTML(t *testing.T) {
+	feed := `<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/"><channel>
+<title>Feed</title><description>About &lt;script&gt;x&lt;/script&gt;</description>
+<item><title>&lt;script&gt;</title><description>Summary &lt;script&gt;y&lt;/script&gt;</description>
+<content:encoded><![CDATA[<p>Body</p><script>z</script>]]></content:encoded></item>
+<item><title>Empty</title></item>
+</channel></rss>`
+
+	calls := 0
+	fp := gofeed.NewParser()
+	fp.PreprocessHTML = func(html string) string {
+		calls++
+		if i := strings.Index(html, "<script>"); i >= 0 {
+			return html[:i]
+		}
+		return html
+	}
+	actual, err := fp.ParseString(feed)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, "About ", actual.Description)
+	assert.Equal(t, "<script>", actual.Items[0].Title)
+	assert.Equal(t, "Summary ", actual.Items[0].Description)
+	assert.Equal(t, "<p>Body</p>", actual.Items[0].Content)
+	assert.Empty(t, actual.Items[1].Description)
+}
+
+func TestRegisterNamespace(t *testing.T) {
+	gofeed.RegisterNamespace("http://example.org/ns/proprietary", "prop")
+
+	feedData := `<rss version="2.0" xmlns:x="http://example.org/ns/proprietary">
+<channel>
+<x:rating>5</x:rating>
+</channel>
+</rss>`
+
+	fp := gofeed.NewParser()
+	feed, err := fp.ParseString(feedData)
+
+	assert.Nil(t, err)
+	assert.NotContains(t, feed.Extensions, "x")
+	assert.Equal(t, "5", feed.Extensions["prop"]["rating"][0].Value)
+}
+
+func TestParser_Limits(t *testing.T) {
+	feedData := `<feed xmlns="http://www.w3.org/2005/Atom"><entry></entry><entry></entry></feed>`
+
+	fp := gofeed.NewParser()
+	fp.MaxItems = 1
+	_, err := fp.ParseString(feedData)
+	assert.ErrorIs(t, err, gofeed.ErrLimitExceeded)
+
+	fp.MaxItems = 2
+	_, err = fp.ParseString(feedData)
+	assert.Nil(t, err)
+
+	// Reading stops at MaxBytes, even for a stream that never ends
+	fp.MaxBytes = 1024
+	endless := io.MultiReader(strings.NewReader(`<rss version="2.0"><channel>`), endlessReader{})
+	_, err = fp.Parse(endless)
+	assert.ErrorIs(t, err, gofeed.ErrLimitExceeded)
+}
+
+// endlessReader is a stream of whitespace that never ends.
+type endlessReader struct{}
+
+func (endlessReader) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = ' '
+	}
+	return len(b), nil
+}
+
 func TestParser_ParseURL_Success(t *testing.T) {
 	var feedTests = []struct {
 		file      string
@@ -148,6 +457,166 @@ func TestParser_ParseURL_Success(t *testing.T) {
 	}
 }
 
+func TestParser_ParseURLWithDiscovery(t *testing.T) {
+	rssFeed, _ := os.ReadFile("testdata/parser/universal/rss_feed.xml")
+	atomFeed, _ := os.ReadFile("testdata/parser/universal/atom10_feed.xml")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `<html><head>
+<link rel="alternate" type="application/rss+xml" href="/feed.rss">
+<link rel="alternate" type="application/atom+xml" href="feed.atom">
+</head></html>`)
+	})
+	mux.HandleFunc("/ambiguous", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `<html><head>
+<link rel="alternate" type="application/rss+xml" href="/posts.rss">
+<link rel="alternate" type="application/rss+xml" href="/comments.rss">
+</head></html>`)
+	})
+	mux.HandleFunc("/feed.rss", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(rssFeed)
+	})
+	mux.HandleFunc("/feed.atom", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(atomFeed)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	fp := gofeed.NewParser()
+
+	// Atom is preferred over RSS
+	feed, resolvedURL, err := fp.ParseURLWithDiscovery(server.URL+"/", context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, server.URL+"/feed.atom", resolvedURL)
+	assert.Equal(t, "atom", feed.FeedType)
+
+	// A feed url resolves to itself
+	feed, resolvedURL, err = fp.ParseURLWithDiscovery(server.URL+"/feed.rss", context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, server.URL+"/feed.rss", resolvedURL)
+	assert.Equal(t, "rss", feed.FeedType)
+
+	_, err = fp.ParseURL(server.URL + "/ambiguous")
+	var derr *gofeed.DiscoveryError
+	if assert.ErrorAs(t, err, &derr) {
+		assert.Equal(t, []string{server.URL + "/posts.rss", server.URL + "/comments.rss"}, derr.Candidates)
+	}
+}
+
+func TestParser_ParseURLWithInfo(t *testing.T) {
+	rssFeed, _ := os.ReadFile("testdata/parser/universal/rss_feed.xml")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/feed.rss", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/feed.rss", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+		w.Header().Set("ETag", `"abc"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		w.Write(rssFeed)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	fp := gofeed.NewParser()
+	feed, info, err := fp.ParseURLWithInfo(server.URL+"/old", context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "rss", feed.FeedType)
+	assert.Equal(t, server.URL+"/feed.rss", info.FinalURL)
+	assert.Equal(t, http.StatusOK, info.StatusCode)
+	assert.Equal(t, "application/rss+xml; charset=utf-8", info.ContentType)
+	assert.Equal(t, `"abc"`, info.ETag)
+	assert.Equal(t, "Mon, 02 Jan 2006 15:04:05 GMT", info.LastModified)
+	assert.Equal(t, `"abc"`, info.Header.Get("ETag"))
+}
+
+func TestParser_ParseResponse(t *testing.T) {
+	rssFeed, _ := os.ReadFile("testdata/parser/universal/rss_feed.xml")
+
+	fp := gofeed.NewParser()
+	feed, err := fp.ParseResponse(&http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(rssFeed))})
+	assert.Nil(t, err)
+	assert.Equal(t, "rss", feed.FeedType)
+
+	_, err = fp.ParseResponse(&http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Body: http.NoBody})
+	var herr gofeed.HTTPError
+	if assert.ErrorAs(t, err, &herr) {
+		assert.Equal(t, http.StatusNotFound, herr.StatusCode)
+	}
+}
+
+func TestParser_ParseURL_Redirects(t *testing.T) {
+	rssFeed, _ := os.ReadFile("testdata/parser/universal/rss_feed.xml")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/feed.rss", func(w http.ResponseWriter, r *http.Request) {
+		if _, _, ok := r.BasicAuth(); ok {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write(rssFeed)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	// Same host, different port, so a different origin
+	redirector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, server.URL+r.URL.Path, http.StatusFound)
+	}))
+	defer redirector.Close()
+
+	secure := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, server.URL+"/feed.rss", http.StatusFound)
+	}))
+	defer secure.Close()
+
+	// Credentials aren't sent along to another origin
+	fp := gofeed.NewParser()
+	fp.AuthConfig = &gofeed.Auth{Username: "foo", Password: "bar"}
+	feed, err := fp.ParseURL(redirector.URL + "/feed.rss")
+	assert.Nil(t, err)
+	assert.NotNil(t, feed)
+
+	fp = gofeed.NewParser()
+	fp.MaxRedirects = 3
+	_, err = fp.ParseURL(server.URL + "/loop")
+	var rerr *gofeed.RedirectError
+	if assert.ErrorAs(t, err, &rerr) {
+		assert.Len(t, rerr.Chain, 5)
+		assert.Equal(t, server.URL+"/loop", rerr.Chain[4])
+	}
+
+	fp = gofeed.NewParser()
+	fp.MaxRedirects = -1
+	_, err = fp.ParseURL(redirector.URL + "/feed.rss")
+	if assert.ErrorAs(t, err, &rerr) {
+		assert.Equal(t, []string{redirector.URL + "/feed.rss", server.URL + "/feed.rss"}, rerr.Chain)
+	}
+
+	fp = gofeed.NewParser()
+	fp.Client = secure.Client()
+	_, err = fp.ParseURL(secure.URL)
+	assert.Nil(t, err)
+
+	fp.RejectInsecureRedirects = true
+	_, err = fp.ParseURL(secure.URL)
+	assert.ErrorIs(t, err, gofeed.ErrInsecureRedirect)
+
+	refused := errors.New("refused")
+	fp = gofeed.NewParser()
+	fp.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return refused
+	}
+	_, err = fp.ParseURL(redirector.URL + "/feed.rss")
+	assert.ErrorIs(t, err, refused)
+}
+
 func TestParser_ParseURLWithContext(t *testing.T) {
 	server, client := mockServerResponse(404, "", 1*time.Minute)
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -158,6 +627,133 @@ func TestParser_ParseURLWithContext(t *testing.T) {
 	assert.True(t, strings.Contains(err.Error(), ctx.Err().Error()))
 }
 
+func TestParser_MaxResponseBytes(t *testing.T) {
+	rssFeed, _ := os.ReadFile("testdata/parser/universal/rss_feed.xml")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/feed.rss", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(rssFeed)
+	})
+	mux.HandleFunc("/chunked.rss", func(w http.ResponseWriter, r *http.Request) {
+		// Flushing early leaves out the Content-Length
+		w.Write(rssFeed[:10])
+		w.(http.Flusher).Flush()
+		w.Write(rssFeed[10:])
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	fp := gofeed.NewParser()
+	fp.MaxResponseBytes = int64(len(rssFeed))
+	for _, path := range []string{"/feed.rss", "/chunked.rss"} {
+		_, err := fp.ParseURL(server.URL + path)
+		assert.Nil(t, err, path)
+	}
+
+	fp.MaxResponseBytes = int64(len(rssFeed)) - 1
+	for _, path := range []string{"/feed.rss", "/chunked.rss"} {
+		_, err := fp.ParseURL(server.URL + path)
+		assert.ErrorIs(t, err, gofeed.ErrResponseTooLarge, path)
+	}
+}
+
+func TestParser_ParseURL_Compressed(t *testing.T) {
+	rssFeed, _ := os.ReadFile("testdata/parser/universal/rss_feed.xml")
+	compress := func(w func(io.Writer) io.WriteCloser, data []byte) []byte {
+		buf := &bytes.Buffer{}
+		cw := w(buf)
+		cw.Write(data)
+		cw.Close()
+		return buf.Bytes()
+	}
+	gzipped := compress(func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }, rssFeed)
+	zlibbed := compress(func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) }, rssFeed)
+	deflated := compress(func(w io.Writer) io.WriteCloser {
+		fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+		return fw
+	}, rssFeed)
+
+	responses := map[string]struct {
+		encoding string
+		body     []byte
+	}{
+		// The transport undoes the gzip encoding it asked for,
+		// leaving the inner gzip stream to the parser.
+		"/twice.rss":      {"gzip", compress(func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }, gzipped)},
+		"/zlib.rss":       {"deflate", zlibbed},
+		"/deflate.rss":    {"deflate", deflated},
+		"/feed.rss.gz":    {"", gzipped},
+		"/corrupt.rss":    {"deflate", []byte("not deflated")},
+		"/truncated.rss":  {"", gzipped[:len(gzipped)/2]},
+		"/identity.rss":   {"identity", rssFeed},
+		"/unexpected.rss": {"x-unknown", rssFeed},
+	}
+	mux := http.NewServeMux()
+	for path, resp := range responses {
+		resp := resp
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			if resp.encoding != "" {
+				w.Header().Set("Content-Encoding", resp.encoding)
+			}
+			w.Write(resp.body)
+		})
+	}
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	fp := gofeed.NewParser()
+	for _, path := range []string{"/twice.rss", "/zlib.rss", "/deflate.rss", "/feed.rss.gz", "/identity.rss", "/unexpected.rss"} {
+		feed, err := fp.ParseURL(server.URL + path)
+		if assert.Nil(t, err, path) {
+			assert.Equal(t, "rss", feed.FeedType, path)
+		}
+	}
+
+	for _, path := range []string{"/corrupt.rss", "/truncated.rss"} {
+		_, err := fp.ParseURL(server.URL + path)
+		if assert.NotNil(t, err, path) {
+			assert.Contains(t, err.Error(), "corrupt", path)
+		}
+	}
+
+	// The decompressed body counts against the limit too
+	fp.MaxResponseBytes = int64(len(rssFeed)) - 1
+	_, err := fp.ParseURL(server.URL + "/zlib.rss")
+	assert.ErrorIs(t, err, gofeed.ErrResponseTooLarge)
+}
+
+func TestParser_ItemIndex(t *testing.T) {
+	feed := `<rss version="2.0"><channel>
+<item><title>Two</title><pubDate>Tue, 02 Jan 2024 00:00:00 GMT</pubDate></item>
+<item><title>Three</title><pubDate>Wed, 03 Jan 2024 00:00:00 GMT</pubDate></item>
+<item><title>One</title><pubDate>Mon, 01 Jan 2024 00:00:00 GMT</pubDate></item>
+</channel></rss>`
+
+	actual, err := gofeed.NewParser().ParseString(feed)
+	assert.Nil(t, err)
+
+	// The position in the document survives reordering
+	sort.Sort(actual)
+	titles := []string{}
+	indexes := []int{}
+	for _, item := range actual.Items {
+		titles = append(titles, item.Title)
+		indexes = append(indexes, item.Index)
+	}
+	assert.Equal(t, []string{"One", "Two", "Three"}, titles)
+	assert.Equal(t, []int{2, 0, 1}, indexes)
+
+	// Null entries of a JSON feed still count
+	feed = `{"version": "https://jsonfeed.org/version/1.1", "title": "Feed",
+"items": [null, {"id": "1"}, null, {"id": "2"}]}`
+	actual, err = gofeed.NewParser().ParseString(feed)
+	assert.Nil(t, err)
+	if assert.Len(t, actual.Items, 2) {
+		assert.Equal(t, 1, actual.Items[0].Index)
+		assert.Equal(t, 3, actual.Items[1].Index)
+	}
+}
+
 func TestParser_ParseURL_Failure(t *testing.T) {
 	server, client := mockServerResponse(404, "", 0)
 	fp := gofeed.NewParser()
@@ -211,6 +807,52 @@ func TestParser_Concurrent(t *testing.T) {
 	wg.Wait()
 }
 
+func FuzzParse(f *testing.F) {
+	// The universal fixtures are seeds next to the tricky
+	// feeds in testdata/fuzz/FuzzParse.
+	files, _ := filepath.Glob("testdata/parser/universal/*")
+	for _, file := range files {
+		if data, err := os.ReadFile(file); err == nil {
+			f.Add(data)
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, strict := range []bool{false, true} {
+			fp := gofeed.NewParser()
+			fp.Strict = strict
+			feed, err := fp.Parse(bytes.NewReader(data))
+			if err == nil && feed == nil {
+				t.Fatal("nil feed without an error")
+			}
+		}
+	})
+}
+
+func BenchmarkParser_Parse(b *testing.B) {
+	feed := &bytes.Buffer{}
+	feed.WriteString(`<?xml version="1.0"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+<channel><title>Benchmark</title><link>http://example.org/</link><description>A feed with 50 items</description>`)
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(feed, `<item><title>Item %d</title><link>http://example.org/%d</link><guid>http://example.org/%d</guid>`+
+			`<pubDate>Mon, 02 Jan 2006 15:04:05 GMT</pubDate><dc:creator>Author</dc:creator><category>News</category>`+
+			`<description>Summary of item %d</description><content:encoded><![CDATA[<p>Content of item %d</p>]]></content:encoded></item>`,
+			i, i, i, i, i)
+	}
+	feed.WriteString(`</channel></rss>`)
+	data := feed.Bytes()
+
+	fp := gofeed.NewParser()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := fp.Parse(bytes.NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 // Test Helpers
 
 func mockServerResponse(code int, body string, delay time.Duration) (*httptest.Server, *http.Client) {
@@ -270,7 +912,7 @@ func ExampleParser_ParseString() {
 	fmt.Println(feed.Title)
 }
 
-func ExampleParserWithBasicAuth_ParseURL() {
+func ExampleParser_ParseURL_withBasicAuth() {
 	fp := gofeed.NewParser()
 	fp.AuthConfig = &gofeed.Auth{
 		Username: "foo",
@@ -0,0 +1,166 @@
+package gofeed
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	ext "github.com/mmcdole/gofeed/extensions"
+)
+
+// RatingLevel is how suitable a feed or item is for all
+// audiences, normalized across the ways feeds express it.
+type RatingLevel int
+
+const (
+	// RatingUnknown is used when no rating says either way
+	RatingUnknown RatingLevel = iota
+	// RatingClean represents content suitable for all audiences
+	RatingClean
+	// RatingExplicit represents content for adults only
+	RatingExplicit
+)
+
+var ratingLevelNames = []string{"unknown", "clean", "explicit"}
+
+func (l RatingLevel) String() string {
+	if l < 0 || int(l) >= len(ratingLevelNames) {
+		return fmt.Sprintf("RatingLevel(%d)", int(l))
+	}
+	return ratingLevelNames[l]
+}
+
+// MarshalText encodes the level by its name, e.g. "explicit".
+func (l RatingLevel) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText decodes a level encoded by MarshalText.
+func (l *RatingLevel) UnmarshalText(text []byte) error {
+	for i, name := range ratingLevelNames {
+		if string(text) == name {
+			*l = RatingLevel(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown rating level %q", text)
+}
+
+// ContentRating is the content rating of a feed or item,
+// gathered from the RSS PICS <rating>, itunes:explicit and
+// media:rating. Level is explicit when any of them says so,
+// clean when one of them says so and none is explicit.
+type ContentRating struct {
+	Level          RatingLevel        `json:"level"`
+	PICS           string             `json:"pics,omitempty"`
+	ITunesExplicit string             `json:"itunesExplicit,omitempty"`
+	MediaRatings   []*ext.MediaRating `json:"mediaRatings,omitempty"`
+}
+
+// newContentRating returns the ContentRating for the given
+// raw ratings, or nil when there are none.
+func newContentRating(pics, explicit string, media []*ext.MediaRating) *ContentRating {
+	if pics == "" && explicit == "" && len(media) == 0 {
+		return nil
+	}
+
+	rating := &ContentRating{PICS: pics, ITunesExplicit: explicit, MediaRatings: media}
+	levels := []RatingLevel{picsLevel(pics), itunesExplicitLevel(explicit)}
+	for _, m := range media {
+		levels = append(levels, mediaRatingLevel(m))
+	}
+	for _, l := range levels {
+		if l > rating.Level {
+			rating.Level = l
+		}
+	}
+	return rating
+}
+
+func itunesExplicitLevel(explicit string) RatingLevel {
+	switch strings.ToLower(strings.TrimSpace(explicit)) {
+	case "yes", "true", "explicit":
+		return RatingExplicit
+	case "no", "false", "clean":
+		return RatingClean
+	}
+	return RatingUnknown
+}
+
+func mediaRatingLevel(rating *ext.MediaRating) RatingLevel {
+	value := strings.ToLower(rating.Value)
+	switch strings.ToLower(rating.Scheme) {
+	case "urn:simple":
+		switch value {
+		case "adult":
+			return RatingExplicit
+		case "nonadult":
+			return RatingClean
+		}
+	case "urn:mpaa":
+		switch value {
+		case "r", "nc-17", "x":
+			return RatingExplicit
+		case "g":
+			return RatingClean
+		}
+	case "urn:v-chip":
+		switch value {
+		case "tv-ma":
+			return RatingExplicit
+		case "tv-y", "tv-y7", "tv-g":
+			return RatingClean
+		}
+	}
+	return RatingUnknown
+}
+
+// picsLevel reads the category values of a PICS label such as
+// `(PICS-1.1 "http://www.rsac.org/ratingsv01.html" l r (n 0 s 0 v 0 l 0))`.
+// Labels rating every category 0 are clean, any other value
+// is taken as explicit.
+func picsLevel(pics string) RatingLevel {
+	i := strings.LastIndex(pics, " r (")
+	if i < 0 {
+		return RatingUnknown
+	}
+	values := pics[i+len(" r ("):]
+	if end := strings.Index(values, ")"); end >= 0 {
+		values = values[:end]
+	}
+
+	fields := strings.Fields(values)
+	if len(fields) == 0 || len(fields)%2 != 0 {
+		return RatingUnknown
+	}
+	for k := 1; k < len(fields); k += 2 {
+		v, err := strconv.ParseFloat(fields[k], 64)
+		if err != nil {
+			return RatingUnknown
+		}
+		if v != 0 {
+			return RatingExplicit
+		}
+	}
+	return RatingClean
+}
+
+// feedMediaRatings returns the media:rating elements of a
+// feed, which has no typed media extension of its own.
+func feedMediaRatings(extensions ext.Extensions) []*ext.MediaRating {
+	if media, ok := extensions["media"]; ok {
+		return ext.NewMediaExtension(media).Ratings
+	}
+	return nil
+}
+
+// itunesExplicit returns the itunes:explicit value of a feed
+// or entry that has no typed iTunes extension.
+func itunesExplicit(extensions ext.Extensions) string {
+	if itunes, ok := extensions["itunes"]; ok {
+		if e, ok := itunes["explicit"]; ok && len(e) > 0 {
+			return e[0].Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+package gofeed
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Redirects followed by ParseURL when the Parser doesn't set
+// MaxRedirects, the same as the net/http default.
+const defaultMaxRedirects = 10
+
+// ErrInsecureRedirect is wrapped by the RedirectError returned
+// when a redirect from https to http is refused, see
+// Parser.RejectInsecureRedirects.
+var ErrInsecureRedirect = errors.New("refusing redirect from https to http")
+
+// RedirectError is returned when fetching a feed stops at a
+// redirect, e.g. because the chain got too long. Chain lists
+// every url that was requested, ending with the redirect
+// target that wasn't followed.
+type RedirectError struct {
+	Chain []string
+	Err   error
+}
+
+func (e *RedirectError) Error() string {
+	return fmt.Sprintf("redirect %s: %v", strings.Join(e.Chain, " -> "), e.Err)
+}
+
+func (e *RedirectError) Unwrap() error {
+	return e.Err
+}
+
+// checkRedirect returns the redirect policy of the client
+// used to fetch feeds, running next, the policy of the
+// configured Client if any, after its own checks.
+func (f *Parser) checkRedirect(next func(*http.Request, []*http.Request) error) func(*http.Request, []*http.Request) error {
+	max := f.MaxRedirects
+	if max == 0 {
+		max = defaultMaxRedirects
+	} else if max < 0 {
+		max = 0
+	}
+
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) > max {
+			return newRedirectError(req, via, fmt.Errorf("stopped after %d redirects", max))
+		}
+		if f.RejectInsecureRedirects && via[len(via)-1].URL.Scheme == "https" && req.URL.Scheme == "http" {
+			return newRedirectError(req, via, ErrInsecureRedirect)
+		}
+
+		// net/http keeps credentials for subdomains, only
+		// ever send them to the origin they were meant for.
+		if !sameOrigin(req.URL, via[0].URL) {
+			req.Header.Del("Authorization")
+		}
+
+		if f.CheckRedirect != nil {
+			if err := f.CheckRedirect(req, via); err != nil {
+				return err
+			}
+		}
+		if next != nil {
+			return next(req, via)
+		}
+		return nil
+	}
+}
+
+func newRedirectError(req *http.Request, via []*http.Request, err error) *RedirectError {
+	chain := make([]string, 0, len(via)+1)
+	for _, r := range via {
+		chain = append(chain, r.URL.String())
+	}
+	return &RedirectError{Chain: append(chain, req.URL.String()), Err: err}
+}
+
+// sameOrigin reports whether a and b share scheme, host
+// and port.
+func sameOrigin(a, b *url.URL) bool {
+	return strings.EqualFold(a.Scheme, b.Scheme) && strings.EqualFold(a.Host, b.Host)
+}
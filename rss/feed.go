@@ -2,6 +2,7 @@ package rss
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
 	ext "github.com/mmcdole/gofeed/extensions"
@@ -9,33 +10,39 @@ import (
 
 // Feed is an RSS Feed
 type Feed struct {
-	Title               string                   `json:"title,omitempty"`
-	Link                string                   `json:"link,omitempty"`
-	Links               []string                 `json:"links,omitempty"`
-	Description         string                   `json:"description,omitempty"`
-	Language            string                   `json:"language,omitempty"`
-	Copyright           string                   `json:"copyright,omitempty"`
-	ManagingEditor      string                   `json:"managingEditor,omitempty"`
-	WebMaster           string                   `json:"webMaster,omitempty"`
-	PubDate             string                   `json:"pubDate,omitempty"`
-	PubDateParsed       *time.Time               `json:"pubDateParsed,omitempty"`
-	LastBuildDate       string                   `json:"lastBuildDate,omitempty"`
-	LastBuildDateParsed *time.Time               `json:"lastBuildDateParsed,omitempty"`
-	Categories          []*Category              `json:"categories,omitempty"`
-	Generator           string                   `json:"generator,omitempty"`
-	Docs                string                   `json:"docs,omitempty"`
-	TTL                 string                   `json:"ttl,omitempty"`
-	Image               *Image                   `json:"image,omitempty"`
-	Rating              string                   `json:"rating,omitempty"`
-	SkipHours           []string                 `json:"skipHours,omitempty"`
-	SkipDays            []string                 `json:"skipDays,omitempty"`
-	Cloud               *Cloud                   `json:"cloud,omitempty"`
-	TextInput           *TextInput               `json:"textInput,omitempty"`
-	DublinCoreExt       *ext.DublinCoreExtension `json:"dcExt,omitempty"`
-	ITunesExt           *ext.ITunesFeedExtension `json:"itunesExt,omitempty"`
-	Extensions          ext.Extensions           `json:"extensions,omitempty"`
-	Items               []*Item                  `json:"items"`
-	Version             string                   `json:"version"`
+	Title               string                        `json:"title,omitempty"`
+	Link                string                        `json:"link,omitempty"`
+	Links               []string                      `json:"links,omitempty"`
+	Description         string                        `json:"description,omitempty"`
+	Language            string                        `json:"language,omitempty"`
+	XMLLang             string                        `json:"xmlLang,omitempty"` // xml:lang of the channel or root element
+	Copyright           string                        `json:"copyright,omitempty"`
+	ManagingEditor      string                        `json:"managingEditor,omitempty"`
+	WebMaster           string                        `json:"webMaster,omitempty"`
+	PubDate             string                        `json:"pubDate,omitempty"`
+	PubDateParsed       *time.Time                    `json:"pubDateParsed,omitempty"`
+	LastBuildDate       string                        `json:"lastBuildDate,omitempty"`
+	LastBuildDateParsed *time.Time                    `json:"lastBuildDateParsed,omitempty"`
+	Categories          []*Category                   `json:"categories,omitempty"`
+	Generator           string                        `json:"generator,omitempty"`
+	Docs                string                        `json:"docs,omitempty"`
+	TTL                 string                        `json:"ttl,omitempty"`
+	TTLParsed           time.Duration                 `json:"ttlParsed,omitempty"`
+	Image               *Image                        `json:"image,omitempty"`
+	Rating              string                        `json:"rating,omitempty"`
+	SkipHours           []string                      `json:"skipHours,omitempty"`
+	SkipDays            []string                      `json:"skipDays,omitempty"`
+	Cloud               *Cloud                        `json:"cloud,omitempty"`
+	TextInput           *TextInput                    `json:"textInput,omitempty"`
+	DublinCoreExt       *ext.DublinCoreExtension      `json:"dcExt,omitempty"`
+	DublinCoreTermsExt  *ext.DublinCoreTermsExtension `json:"dctermsExt,omitempty"`
+	ITunesExt           *ext.ITunesFeedExtension      `json:"itunesExt,omitempty"`
+	Extensions          ext.Extensions                `json:"extensions,omitempty"`
+	Namespaces          map[string]string             `json:"namespaces,omitempty"` // Declared on the root element, url to prefix
+	ItemCount           int                           `json:"itemCount,omitempty"`  // Only set with Parser.MetadataOnly
+	Items               []*Item                       `json:"items"`
+	Version             string                        `json:"version"`
+	Warnings            []ParseWarning                `json:"warnings,omitempty"`
 }
 
 func (f Feed) String() string {
@@ -45,24 +52,26 @@ func (f Feed) String() string {
 
 // Item is an RSS Item
 type Item struct {
-	Title         string                   `json:"title,omitempty"`
-	Link          string                   `json:"link,omitempty"`
-	Links         []string                 `json:"links,omitempty"`
-	Description   string                   `json:"description,omitempty"`
-	Content       string                   `json:"content,omitempty"`
-	Author        string                   `json:"author,omitempty"`
-	Categories    []*Category              `json:"categories,omitempty"`
-	Comments      string                   `json:"comments,omitempty"`
-	Enclosure     *Enclosure               `json:"enclosure,omitempty"`
-	Enclosures    []*Enclosure             `json:"enclosures,omitempty"`
-	GUID          *GUID                    `json:"guid,omitempty"`
-	PubDate       string                   `json:"pubDate,omitempty"`
-	PubDateParsed *time.Time               `json:"pubDateParsed,omitempty"`
-	Source        *Source                  `json:"source,omitempty"`
-	DublinCoreExt *ext.DublinCoreExtension `json:"dcExt,omitempty"`
-	ITunesExt     *ext.ITunesItemExtension `json:"itunesExt,omitempty"`
-	Extensions    ext.Extensions           `json:"extensions,omitempty"`
-	Custom        map[string]string        `json:"custom,omitempty"`
+	Title              string                        `json:"title,omitempty"`
+	Link               string                        `json:"link,omitempty"`
+	Links              []string                      `json:"links,omitempty"`
+	Description        string                        `json:"description,omitempty"`
+	Content            string                        `json:"content,omitempty"`
+	Author             string                        `json:"author,omitempty"`
+	Categories         []*Category                   `json:"categories,omitempty"`
+	Comments           string                        `json:"comments,omitempty"`
+	Enclosure          *Enclosure                    `json:"enclosure,omitempty"`
+	Enclosures         []*Enclosure                  `json:"enclosures,omitempty"`
+	GUID               *GUID                         `json:"guid,omitempty"`
+	PubDate            string                        `json:"pubDate,omitempty"`
+	PubDateParsed      *time.Time                    `json:"pubDateParsed,omitempty"`
+	Source             *Source                       `json:"source,omitempty"`
+	DublinCoreExt      *ext.DublinCoreExtension      `json:"dcExt,omitempty"`
+	DublinCoreTermsExt *ext.DublinCoreTermsExtension `json:"dctermsExt,omitempty"`
+	ITunesExt          *ext.ITunesItemExtension      `json:"itunesExt,omitempty"`
+	MediaExt           *ext.MediaExtension           `json:"mediaExt,omitempty"`
+	Extensions         ext.Extensions                `json:"extensions,omitempty"`
+	Custom             map[string]string             `json:"custom,omitempty"`
 }
 
 // Image is an image that represents the feed
@@ -72,15 +81,18 @@ type Image struct {
 	Title       string `json:"title,omitempty"`
 	Width       string `json:"width,omitempty"`
 	Height      string `json:"height,omitempty"`
+	WidthInt    int    `json:"widthInt,omitempty"`
+	HeightInt   int    `json:"heightInt,omitempty"`
 	Description string `json:"description,omitempty"`
 }
 
 // Enclosure is a media object that is attached to
 // the item
 type Enclosure struct {
-	URL    string `json:"url,omitempty"`
-	Length string `json:"length,omitempty"`
-	Type   string `json:"type,omitempty"`
+	URL       string `json:"url,omitempty"`
+	Length    string `json:"length,omitempty"`
+	LengthInt int64  `json:"lengthInt,omitempty"`
+	Type      string `json:"type,omitempty"`
 }
 
 // GUID is a unique identifier for an item
@@ -122,3 +134,31 @@ type Cloud struct {
 	RegisterProcedure string `json:"registerProcedure,omitempty"`
 	Protocol          string `json:"protocol,omitempty"`
 }
+
+// ParseWarning describes a non-fatal issue that was
+// encountered while parsing the feed
+type ParseWarning struct {
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+func (w ParseWarning) String() string {
+	return w.Path + ": " + w.Message
+}
+
+// ParseError is returned when a feed can not be parsed. It
+// records where in the document parsing stopped.
+type ParseError struct {
+	Offset int64  // Byte offset into the document
+	Line   int    // Line number, starting at 1
+	Path   string // Element path (e.g. "rss > channel > item[3] > pubDate")
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d, offset %d: %s: %v", e.Line, e.Offset, e.Path, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
@@ -3,26 +3,185 @@ package rss
 import (
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
+	"time"
 
 	ext "github.com/mmcdole/gofeed/extensions"
 	"github.com/mmcdole/gofeed/internal/shared"
 	xpp "github.com/mmcdole/goxpp"
 )
 
+// Default dimensions of a channel image when
+// the feed doesn't specify them.
+const (
+	defaultImageWidth  = 88
+	defaultImageHeight = 31
+)
+
+// Namespace of the attributes with the reserved xml prefix,
+// such as xml:lang.
+const xmlNS = "http://www.w3.org/XML/1998/namespace"
+
+// ErrLimitExceeded is wrapped by the error returned when a
+// feed goes over one of the parser's limits.
+var ErrLimitExceeded = shared.ErrLimitExceeded
+
 // Parser is a RSS Parser
-type Parser struct{}
+type Parser struct {
+	// Limits guarding against hostile feeds. Zero values
+	// fall back to generous defaults, negative values
+	// disable the limit.
+	MaxItems        int   // Maximum number of items
+	MaxElementDepth int   // Maximum nesting of elements
+	MaxBytes        int64 // Maximum size of the document
+
+	// DecodeCDATA applies a single level of entity decoding to
+	// CDATA sections in the channel and item description and
+	// in content:encoded, the same decoding escaped text gets.
+	// By default CDATA sections are kept verbatim.
+	DecodeCDATA bool
+
+	// DedupCategories drops channel and item categories whose
+	// value and domain are both identical to an earlier one.
+	DedupCategories bool
+
+	// DisableExtensions skips extension elements instead of
+	// collecting them, which saves the allocations of building
+	// their trees. Extensions and the typed extensions such as
+	// ITunesExt are then left nil.
+	DisableExtensions bool
+
+	// Charset overrides the encoding named by the XML
+	// declaration, e.g. with the charset of the HTTP response
+	// the feed came from. "utf-8" parses the input as it is,
+	// any other label known to golang.org/x/net/html/charset
+	// decodes the input from that encoding.
+	Charset string
+
+	// Strict rejects documents that aren't well-formed XML,
+	// e.g. with undefined entities or mismatched tags, instead
+	// of parsing them leniently.
+	Strict bool
+
+	// MetadataOnly parses the channel but skips over the
+	// items without parsing them, only counting them in
+	// Feed.ItemCount. Feed.Items is left empty.
+	MetadataOnly bool
+
+	// State for a single call to Parse. It is only ever
+	// set on the copy of the Parser made by Parse.
+	limits      shared.Limits
+	prefixes    shared.Prefixes
+	path        shared.ElementPath
+	items       int
+	warnings    []ParseWarning
+	itemHandler func(*Item) error
+}
 
 // Parse parses an xml feed into an rss.Feed
 func (rp *Parser) Parse(feed io.Reader) (*Feed, error) {
-	p := xpp.NewXMLPullParser(feed, false, shared.NewReaderLabel)
-
-	_, err := shared.FindRoot(p)
+	// Parse using a copy of the parser so the per-parse state
+	// is never shared between concurrent calls to Parse.
+	s := *rp
+	s.limits = shared.NewLimits(s.MaxItems, s.MaxElementDepth, s.MaxBytes)
+	s.prefixes = shared.Prefixes{}
+
+	pr := shared.NewPositionReader(feed, s.limits.MaxBytes)
+	defer pr.Release()
+	r, cr, err := shared.DecodeCharset(s.Charset, pr)
 	if err != nil {
 		return nil, err
 	}
+	p := xpp.NewXMLPullParser(r, s.Strict, cr)
+
+	_, err = shared.FindRoot(p)
+	if err != nil {
+		return nil, s.parseError(p, pr, err)
+	}
+
+	result, err := s.parseRoot(p)
+	if err != nil {
+		return nil, s.parseError(p, pr, err)
+	}
+	return result, nil
+}
+
+// ParseStream parses an xml feed like Parse, but hands each
+// item to handler as soon as it has been parsed instead of
+// collecting them in Feed.Items, so memory use doesn't grow
+// with the number of items. An error returned by handler
+// aborts parsing and is returned wrapped in a ParseError.
+//
+// The returned Feed holds the channel metadata only, since
+// it may follow the items in the document. Duplicate guids
+// aren't reported as the items are not retained.
+func (rp *Parser) ParseStream(feed io.Reader, handler func(*Item) error) (*Feed, error) {
+	s := *rp
+	s.itemHandler = handler
+	return s.Parse(feed)
+}
+
+// collectItem hands item to the stream handler, or appends
+// it to items when not streaming.
+func (rp *Parser) collectItem(items []*Item, item *Item) ([]*Item, error) {
+	if rp.itemHandler == nil {
+		return append(items, item), nil
+	}
+	return items, rp.itemHandler(item)
+}
+
+// parseError wraps err with the position in the document and
+// the path of the element being parsed when it occurred. The
+// path is only popped when an element parses successfully, so
+// on failure it still points at the innermost element.
+func (rp *Parser) parseError(p *xpp.XMLPullParser, pr *shared.PositionReader, err error) error {
+	child := ""
+	if p.Depth > rp.path.Depth() {
+		child = p.Name
+	}
+	offset, line := pr.Position(err)
+	return &ParseError{
+		Offset: offset,
+		Line:   line,
+		Path:   rp.path.Render(child),
+		Err:    err,
+	}
+}
+
+// warn records a non-fatal issue found in the given
+// child element of the element currently being parsed.
+func (rp *Parser) warn(element string, format string, args ...interface{}) {
+	rp.warnings = append(rp.warnings, ParseWarning{
+		Path:    rp.path.Render(element),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// parseExtension adds the extension element the parser is
+// on to extensions, or skips it if extensions are disabled.
+func (rp *Parser) parseExtension(extensions ext.Extensions, p *xpp.XMLPullParser) (ext.Extensions, error) {
+	if rp.DisableExtensions {
+		return extensions, p.Skip()
+	}
+	return rp.limits.ParseExtension(extensions, p, rp.prefixes)
+}
 
-	return rp.parseRoot(p)
+// warnDuplicateGUIDs records a warning for every item
+// whose guid was already used by an earlier item.
+func (rp *Parser) warnDuplicateGUIDs(items []*Item) {
+	guid := func(i int) string {
+		if items[i].GUID == nil {
+			return ""
+		}
+		return items[i].GUID.Value
+	}
+	shared.FindDuplicates(len(items), guid, func(first, dup int) {
+		rp.warnings = append(rp.warnings, ParseWarning{
+			Path:    rp.path.Render(fmt.Sprintf("item[%d] > guid", dup)),
+			Message: fmt.Sprintf("duplicate guid %q also used by item[%d]", guid(dup), first),
+		})
+	})
 }
 
 func (rp *Parser) parseRoot(p *xpp.XMLPullParser) (*Feed, error) {
@@ -39,9 +198,13 @@ func (rp *Parser) parseRoot(p *xpp.XMLPullParser) (*Feed, error) {
 	items := []*Item{}
 
 	ver := rp.parseVersion(p)
+	lang := shared.AttributeNS(p, xmlNS, "lang")
+	namespaces := shared.DeclaredNamespaces(p)
+
+	rp.path.Push(p.Name, -1)
 
 	for {
-		tok, err := shared.NextTag(p)
+		tok, err := rp.limits.NextTag(p)
 		if err != nil {
 			return nil, err
 		}
@@ -53,7 +216,7 @@ func (rp *Parser) parseRoot(p *xpp.XMLPullParser) (*Feed, error) {
 		if tok == xpp.StartTag {
 
 			// Skip any extensions found in the feed root.
-			if shared.IsExtension(p) {
+			if rp.prefixes.IsExtension(p) {
 				p.Skip()
 				continue
 			}
@@ -65,12 +228,18 @@ func (rp *Parser) parseRoot(p *xpp.XMLPullParser) (*Feed, error) {
 				if err != nil {
 					return nil, err
 				}
+			} else if name == "item" && rp.MetadataOnly {
+				if err := rp.skipItem(p); err != nil {
+					return nil, err
+				}
 			} else if name == "item" {
 				item, err := rp.parseItem(p)
 				if err != nil {
 					return nil, err
 				}
-				items = append(items, item)
+				if items, err = rp.collectItem(items, item); err != nil {
+					return nil, err
+				}
 			} else if name == "textinput" {
 				textinput, err = rp.parseTextInput(p)
 				if err != nil {
@@ -82,6 +251,7 @@ func (rp *Parser) parseRoot(p *xpp.XMLPullParser) (*Feed, error) {
 					return nil, err
 				}
 			} else {
+				rp.warn(p.Name, "unexpected element skipped")
 				p.Skip()
 			}
 		}
@@ -99,6 +269,7 @@ func (rp *Parser) parseRoot(p *xpp.XMLPullParser) (*Feed, error) {
 	}
 
 	if len(items) > 0 {
+		rp.warnDuplicateGUIDs(items)
 		channel.Items = append(channel.Items, items...)
 	}
 
@@ -110,7 +281,18 @@ func (rp *Parser) parseRoot(p *xpp.XMLPullParser) (*Feed, error) {
 		channel.Image = image
 	}
 
+	if channel.XMLLang == "" {
+		channel.XMLLang = lang
+	}
+
+	if rp.MetadataOnly {
+		channel.ItemCount = rp.items
+	}
+
+	channel.Namespaces = namespaces
 	channel.Version = ver
+	channel.Warnings = rp.warnings
+	rp.path.Pop()
 	return channel, nil
 }
 
@@ -119,15 +301,19 @@ func (rp *Parser) parseChannel(p *xpp.XMLPullParser) (rss *Feed, err error) {
 		return nil, err
 	}
 
+	rp.path.Push("channel", -1)
+
 	rss = &Feed{}
 	rss.Items = []*Item{}
+	rss.XMLLang = shared.AttributeNS(p, xmlNS, "lang")
 
-	extensions := ext.Extensions{}
+	extensions := shared.GetExtensions()
+	defer shared.PutExtensions(extensions)
 	categories := []*Category{}
 	links := []string{}
 
 	for {
-		tok, err := shared.NextTag(p)
+		tok, err := rp.limits.NextTag(p)
 		if err != nil {
 			return nil, err
 		}
@@ -140,8 +326,8 @@ func (rp *Parser) parseChannel(p *xpp.XMLPullParser) (rss *Feed, err error) {
 
 			name := strings.ToLower(p.Name)
 
-			if shared.IsExtension(p) {
-				ext, err := shared.ParseExtension(extensions, p)
+			if rp.prefixes.IsExtension(p) {
+				ext, err := rp.parseExtension(extensions, p)
 				if err != nil {
 					return nil, err
 				}
@@ -153,7 +339,7 @@ func (rp *Parser) parseChannel(p *xpp.XMLPullParser) (rss *Feed, err error) {
 				}
 				rss.Title = result
 			} else if name == "description" {
-				result, err := shared.ParseText(p)
+				result, err := shared.ParseHTMLText(p, rp.DecodeCDATA)
 				if err != nil {
 					return nil, err
 				}
@@ -199,6 +385,8 @@ func (rp *Parser) parseChannel(p *xpp.XMLPullParser) (rss *Feed, err error) {
 				if err == nil {
 					utcDate := date.UTC()
 					rss.PubDateParsed = &utcDate
+				} else {
+					rp.warn(p.Name, "unparseable date %q", result)
 				}
 			} else if name == "lastbuilddate" {
 				result, err := shared.ParseText(p)
@@ -210,6 +398,8 @@ func (rp *Parser) parseChannel(p *xpp.XMLPullParser) (rss *Feed, err error) {
 				if err == nil {
 					utcDate := date.UTC()
 					rss.LastBuildDateParsed = &utcDate
+				} else {
+					rp.warn(p.Name, "unparseable date %q", result)
 				}
 			} else if name == "generator" {
 				result, err := shared.ParseText(p)
@@ -229,6 +419,7 @@ func (rp *Parser) parseChannel(p *xpp.XMLPullParser) (rss *Feed, err error) {
 					return nil, err
 				}
 				rss.TTL = result
+				rss.TTLParsed = rp.parseTTL(result)
 			} else if name == "rating" {
 				result, err := shared.ParseText(p)
 				if err != nil {
@@ -247,12 +438,18 @@ func (rp *Parser) parseChannel(p *xpp.XMLPullParser) (rss *Feed, err error) {
 					return nil, err
 				}
 				rss.SkipDays = result
+			} else if name == "item" && rp.MetadataOnly {
+				if err := rp.skipItem(p); err != nil {
+					return nil, err
+				}
 			} else if name == "item" {
 				result, err := rp.parseItem(p)
 				if err != nil {
 					return nil, err
 				}
-				rss.Items = append(rss.Items, result)
+				if rss.Items, err = rp.collectItem(rss.Items, result); err != nil {
+					return nil, err
+				}
 			} else if name == "cloud" {
 				result, err := rp.parseCloud(p)
 				if err != nil {
@@ -277,9 +474,14 @@ func (rp *Parser) parseChannel(p *xpp.XMLPullParser) (rss *Feed, err error) {
 					return nil, err
 				}
 				rss.TextInput = result
+			} else if name == "items" {
+				// RSS 1.0 lists the item resources in the channel,
+				// the items themselves are parsed from the root.
+				p.Skip()
 			} else {
 				// Skip element as it isn't an extension and not
 				// part of the spec
+				rp.warn(p.Name, "unexpected element skipped")
 				p.Skip()
 			}
 		}
@@ -289,6 +491,10 @@ func (rp *Parser) parseChannel(p *xpp.XMLPullParser) (rss *Feed, err error) {
 		return nil, err
 	}
 
+	if rp.DedupCategories {
+		categories = dedupCategories(categories)
+	}
+
 	if len(categories) > 0 {
 		rss.Categories = categories
 	}
@@ -297,6 +503,8 @@ func (rp *Parser) parseChannel(p *xpp.XMLPullParser) (rss *Feed, err error) {
 		rss.Links = links
 	}
 
+	rp.warnDuplicateGUIDs(rss.Items)
+
 	if len(extensions) > 0 {
 		rss.Extensions = extensions
 
@@ -307,24 +515,45 @@ func (rp *Parser) parseChannel(p *xpp.XMLPullParser) (rss *Feed, err error) {
 		if dc, ok := rss.Extensions["dc"]; ok {
 			rss.DublinCoreExt = ext.NewDublinCoreExtension(dc)
 		}
+
+		if dcterms, ok := rss.Extensions["dcterms"]; ok {
+			rss.DublinCoreTermsExt = ext.NewDublinCoreTermsExtension(dcterms)
+		}
 	}
 
+	rp.path.Pop()
 	return rss, nil
 }
 
+// skipItem counts an item without parsing it.
+func (rp *Parser) skipItem(p *xpp.XMLPullParser) error {
+	rp.items++
+	if err := rp.limits.CheckItems(rp.items); err != nil {
+		return err
+	}
+	return p.Skip()
+}
+
 func (rp *Parser) parseItem(p *xpp.XMLPullParser) (item *Item, err error) {
 	if err = p.Expect(xpp.StartTag, "item"); err != nil {
 		return nil, err
 	}
 
+	rp.path.Push("item", rp.items)
+	rp.items++
+	if err := rp.limits.CheckItems(rp.items); err != nil {
+		return nil, err
+	}
+
 	item = &Item{}
-	extensions := ext.Extensions{}
+	extensions := shared.GetExtensions()
+	defer shared.PutExtensions(extensions)
 	categories := []*Category{}
 	enclosures := []*Enclosure{}
 	links := []string{}
 
 	for {
-		tok, err := shared.NextTag(p)
+		tok, err := rp.limits.NextTag(p)
 		if err != nil {
 			return nil, err
 		}
@@ -337,12 +566,12 @@ func (rp *Parser) parseItem(p *xpp.XMLPullParser) (item *Item, err error) {
 
 			name := strings.ToLower(p.Name)
 
-			if shared.IsExtension(p) {
-				ext, err := shared.ParseExtension(extensions, p)
+			if rp.prefixes.IsExtension(p) {
+				ext, err := rp.parseExtension(extensions, p)
 				if err != nil {
 					return nil, err
 				}
-				item.Extensions = ext
+				extensions = ext
 			} else if name == "title" {
 				result, err := shared.ParseText(p)
 				if err != nil {
@@ -350,16 +579,16 @@ func (rp *Parser) parseItem(p *xpp.XMLPullParser) (item *Item, err error) {
 				}
 				item.Title = result
 			} else if name == "description" {
-				result, err := shared.ParseText(p)
+				result, err := shared.ParseHTMLText(p, rp.DecodeCDATA)
 				if err != nil {
 					return nil, err
 				}
 				item.Description = result
 			} else if name == "encoded" {
 				space := strings.TrimSpace(p.Space)
-				prefix := shared.PrefixForNamespace(space, p)
+				prefix := rp.prefixes.Prefix(space, p)
 				if prefix == "content" {
-					result, err := shared.ParseText(p)
+					result, err := shared.ParseHTMLText(p, rp.DecodeCDATA)
 					if err != nil {
 						return nil, err
 					}
@@ -394,6 +623,8 @@ func (rp *Parser) parseItem(p *xpp.XMLPullParser) (item *Item, err error) {
 				if err == nil {
 					utcDate := date.UTC()
 					item.PubDateParsed = &utcDate
+				} else {
+					rp.warn(p.Name, "unparseable date %q", result)
 				}
 			} else if name == "source" {
 				result, err := rp.parseSource(p)
@@ -433,10 +664,23 @@ func (rp *Parser) parseItem(p *xpp.XMLPullParser) (item *Item, err error) {
 		}
 	}
 
+	// Hybrid feeds may attach media with an embedded
+	// <atom:link rel="enclosure"> instead of <enclosure>.
+	for _, enc := range rp.parseAtomEnclosures(extensions) {
+		if item.Enclosure == nil {
+			item.Enclosure = enc
+		}
+		enclosures = append(enclosures, enc)
+	}
+
 	if len(enclosures) > 0 {
 		item.Enclosures = enclosures
 	}
 
+	if rp.DedupCategories {
+		categories = dedupCategories(categories)
+	}
+
 	if len(categories) > 0 {
 		item.Categories = categories
 	}
@@ -455,12 +699,21 @@ func (rp *Parser) parseItem(p *xpp.XMLPullParser) (item *Item, err error) {
 		if dc, ok := item.Extensions["dc"]; ok {
 			item.DublinCoreExt = ext.NewDublinCoreExtension(dc)
 		}
+
+		if dcterms, ok := item.Extensions["dcterms"]; ok {
+			item.DublinCoreTermsExt = ext.NewDublinCoreTermsExtension(dcterms)
+		}
+
+		if media, ok := item.Extensions["media"]; ok {
+			item.MediaExt = ext.NewMediaExtension(media)
+		}
 	}
 
 	if err = p.Expect(xpp.EndTag, "item"); err != nil {
 		return nil, err
 	}
 
+	rp.path.Pop()
 	return item, nil
 }
 
@@ -476,6 +729,23 @@ func (rp *Parser) parseLink(p *xpp.XMLPullParser) (url string, err error) {
 	return url, err
 }
 
+func (rp *Parser) parseAtomEnclosures(extensions ext.Extensions) (enclosures []*Enclosure) {
+	for _, key := range []string{"atom", "atom10", "atom03"} {
+		for _, l := range extensions[key]["link"] {
+			if l.Attrs["rel"] != "enclosure" {
+				continue
+			}
+			enclosure := &Enclosure{}
+			enclosure.URL = l.Attrs["href"]
+			enclosure.Type = l.Attrs["type"]
+			enclosure.Length = l.Attrs["length"]
+			enclosure.LengthInt = shared.ParseByteCount(enclosure.Length)
+			enclosures = append(enclosures, enclosure)
+		}
+	}
+	return
+}
+
 func (rp *Parser) parseSource(p *xpp.XMLPullParser) (source *Source, err error) {
 	if err = p.Expect(xpp.StartTag, "source"); err != nil {
 		return nil, err
@@ -483,6 +753,12 @@ func (rp *Parser) parseSource(p *xpp.XMLPullParser) (source *Source, err error)
 
 	source = &Source{}
 	source.URL = p.Attribute("url")
+	if base := p.BaseStack.Top(); base != nil && source.URL != "" {
+		resolved, err := shared.XmlBaseResolveUrl(base, source.URL)
+		if resolved != nil && err == nil {
+			source.URL = resolved.String()
+		}
+	}
 
 	result, err := shared.ParseText(p)
 	if err != nil {
@@ -504,6 +780,7 @@ func (rp *Parser) parseEnclosure(p *xpp.XMLPullParser) (enclosure *Enclosure, er
 	enclosure = &Enclosure{}
 	enclosure.URL = p.Attribute("url")
 	enclosure.Length = p.Attribute("length")
+	enclosure.LengthInt = shared.ParseByteCount(enclosure.Length)
 	enclosure.Type = p.Attribute("type")
 
 	// Ignore any enclosure tag
@@ -513,7 +790,7 @@ func (rp *Parser) parseEnclosure(p *xpp.XMLPullParser) (enclosure *Enclosure, er
 			return enclosure, err
 		}
 
-		if p.Event == xpp.EndTag && p.Name == "enclosure" {
+		if p.Event == xpp.EndTag && strings.EqualFold(p.Name, "enclosure") {
 			break
 		}
 	}
@@ -529,7 +806,7 @@ func (rp *Parser) parseImage(p *xpp.XMLPullParser) (image *Image, err error) {
 	image = &Image{}
 
 	for {
-		tok, err := shared.NextTag(p)
+		tok, err := rp.limits.NextTag(p)
 		if err != nil {
 			return image, err
 		}
@@ -587,6 +864,9 @@ func (rp *Parser) parseImage(p *xpp.XMLPullParser) (image *Image, err error) {
 		return nil, err
 	}
 
+	image.WidthInt = rp.parseImageDimension(image.Width, defaultImageWidth)
+	image.HeightInt = rp.parseImageDimension(image.Height, defaultImageHeight)
+
 	return image, nil
 }
 
@@ -596,7 +876,14 @@ func (rp *Parser) parseGUID(p *xpp.XMLPullParser) (guid *GUID, err error) {
 	}
 
 	guid = &GUID{}
-	guid.IsPermalink = p.Attribute("isPermalink")
+	// The spec spells it isPermaLink, but feeds get the
+	// case wrong often enough to accept any spelling.
+	for _, attr := range p.Attrs {
+		if strings.EqualFold(attr.Name.Local, "isPermaLink") {
+			guid.IsPermalink = attr.Value
+			break
+		}
+	}
 
 	result, err := shared.ParseText(p)
 	if err != nil {
@@ -611,6 +898,21 @@ func (rp *Parser) parseGUID(p *xpp.XMLPullParser) (guid *GUID, err error) {
 	return guid, nil
 }
 
+// dedupCategories removes the categories that repeat both
+// the value and the domain of an earlier category.
+func dedupCategories(categories []*Category) []*Category {
+	seen := make(map[Category]bool, len(categories))
+	unique := categories[:0]
+	for _, c := range categories {
+		if seen[*c] {
+			continue
+		}
+		seen[*c] = true
+		unique = append(unique, c)
+	}
+	return unique
+}
+
 func (rp *Parser) parseCategory(p *xpp.XMLPullParser) (cat *Category, err error) {
 	if err = p.Expect(xpp.StartTag, "category"); err != nil {
 		return nil, err
@@ -640,7 +942,7 @@ func (rp *Parser) parseTextInput(p *xpp.XMLPullParser) (*TextInput, error) {
 	ti := &TextInput{}
 
 	for {
-		tok, err := shared.NextTag(p)
+		tok, err := rp.limits.NextTag(p)
 		if err != nil {
 			return nil, err
 		}
@@ -697,7 +999,7 @@ func (rp *Parser) parseSkipHours(p *xpp.XMLPullParser) ([]string, error) {
 	hours := []string{}
 
 	for {
-		tok, err := shared.NextTag(p)
+		tok, err := rp.limits.NextTag(p)
 		if err != nil {
 			return nil, err
 		}
@@ -735,7 +1037,7 @@ func (rp *Parser) parseSkipDays(p *xpp.XMLPullParser) ([]string, error) {
 	days := []string{}
 
 	for {
-		tok, err := shared.NextTag(p)
+		tok, err := rp.limits.NextTag(p)
 		if err != nil {
 			return nil, err
 		}
@@ -777,7 +1079,7 @@ func (rp *Parser) parseCloud(p *xpp.XMLPullParser) (*Cloud, error) {
 	cloud.RegisterProcedure = p.Attribute("registerProcedure")
 	cloud.Protocol = p.Attribute("protocol")
 
-	shared.NextTag(p)
+	rp.limits.NextTag(p)
 
 	if err := p.Expect(xpp.EndTag, "cloud"); err != nil {
 		return nil, err
@@ -786,6 +1088,31 @@ func (rp *Parser) parseCloud(p *xpp.XMLPullParser) (*Cloud, error) {
 	return cloud, nil
 }
 
+// parseImageDimension converts an image width or height
+// into pixels. A missing value yields the spec default and
+// a non-numeric value yields zero.
+func (rp *Parser) parseImageDimension(value string, def int) int {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return def
+	}
+	pixels, err := strconv.Atoi(value)
+	if err != nil {
+		return 0
+	}
+	return pixels
+}
+
+// parseTTL converts the number of minutes in a <ttl> element
+// into a time.Duration. Non-numeric values yield zero.
+func (rp *Parser) parseTTL(ttl string) time.Duration {
+	minutes, err := strconv.Atoi(strings.TrimSpace(ttl))
+	if err != nil || minutes < 0 {
+		return 0
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
 func (rp *Parser) parseVersion(p *xpp.XMLPullParser) (ver string) {
 	name := strings.ToLower(p.Name)
 	if name == "rss" {
@@ -3,6 +3,7 @@ package rss_test
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -45,4 +46,198 @@ func TestParser_Parse(t *testing.T) {
 	}
 }
 
+func TestParser_DecodeCDATA(t *testing.T) {
+	files, _ := filepath.Glob("../testdata/parser/rss_decode_cdata/*.json")
+	for _, f := range files {
+		base := filepath.Base(f)
+		name := strings.TrimSuffix(base, filepath.Ext(base))
+
+		fmt.Printf("Testing %s with DecodeCDATA... ", name)
+
+		// The source feeds are shared with TestParser_Parse
+		ff := fmt.Sprintf("../testdata/parser/rss/%s.xml", name)
+		f, _ := os.ReadFile(ff)
+
+		fp := &rss.Parser{DecodeCDATA: true}
+		actual, _ := fp.Parse(bytes.NewReader(f))
+
+		ef := fmt.Sprintf("../testdata/parser/rss_decode_cdata/%s.json", name)
+		e, _ := os.ReadFile(ef)
+
+		expected := &rss.Feed{}
+		json.Unmarshal(e, &expected)
+
+		if assert.Equal(t, expected, actual, "Feed file %s.xml did not match expected output %s.json", name, name) {
+			fmt.Printf("OK\n")
+		} else {
+			fmt.Printf("Failed\n")
+		}
+	}
+}
+
+func TestParser_ParseError(t *testing.T) {
+	feed := "<rss version=\"2.0\">\n<channel>\n<item></item>\n<item>\n<pubDate>Mon"
+
+	fp := &rss.Parser{}
+	_, err := fp.Parse(strings.NewReader(feed))
+
+	var perr *rss.ParseError
+	if assert.ErrorAs(t, err, &perr) {
+		assert.Equal(t, 5, perr.Line)
+		assert.Equal(t, int64(len(feed)), perr.Offset)
+		assert.Equal(t, "rss > channel > item[1] > pubDate", perr.Path)
+	}
+}
+
+func TestParser_Limits(t *testing.T) {
+	items := "<rss version=\"2.0\"><channel>" + strings.Repeat("<item></item>", 3) + "</channel></rss>"
+	nested := "<rss version=\"2.0\"><channel><item><x:a xmlns:x=\"http://example.org/x\">" +
+		strings.Repeat("<x:a>", 20) + strings.Repeat("</x:a>", 20) + "</x:a></item></channel></rss>"
+
+	var limitTests = []struct {
+		name   string
+		parser *rss.Parser
+		feed   string
+		fails  bool
+	}{
+		{"items within limit", &rss.Parser{MaxItems: 3}, items, false},
+		{"too many items", &rss.Parser{MaxItems: 2}, items, true},
+		{"items limit disabled", &rss.Parser{MaxItems: -1}, items, false},
+		{"depth within limit", &rss.Parser{MaxElementDepth: 32}, nested, false},
+		{"too deep", &rss.Parser{MaxElementDepth: 16}, nested, true},
+		{"bytes within limit", &rss.Parser{MaxBytes: int64(len(items))}, items, false},
+		{"too many bytes", &rss.Parser{MaxBytes: int64(len(items)) - 1}, items, true},
+	}
+
+	for _, test := range limitTests {
+		_, err := test.parser.Parse(strings.NewReader(test.feed))
+		if test.fails {
+			assert.ErrorIs(t, err, rss.ErrLimitExceeded, test.name)
+		} else {
+			assert.NoError(t, err, test.name)
+		}
+	}
+}
+
+func TestParser_DedupCategories(t *testing.T) {
+	feed := `<rss version="2.0"><channel><item>
+<category domain="http://example.org/tax">Music/Jazz</category>
+<category domain="http://example.org/tax">Music/Jazz</category>
+<category domain="http://example.org/tax"></category>
+</item></channel></rss>`
+
+	fp := &rss.Parser{DedupCategories: true}
+	actual, err := fp.Parse(strings.NewReader(feed))
+
+	assert.Nil(t, err)
+	assert.Equal(t, []*rss.Category{
+		{Domain: "http://example.org/tax", Value: "Music/Jazz"},
+		{Domain: "http://example.org/tax"},
+	}, actual.Items[0].Categories)
+}
+
+func TestParser_ParseStream(t *testing.T) {
+	feed := `<rss version="2.0"><channel><item><title>One</title></item>
+<item><title>Two</title></item><title>Channel</title><item><title>Three</title></item></channel></rss>`
+
+	fp := &rss.Parser{}
+	titles := []string{}
+	actual, err := fp.ParseStream(strings.NewReader(feed), func(item *rss.Item) error {
+		titles = append(titles, item.Title)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"One", "Two", "Three"}, titles)
+	assert.Equal(t, "Channel", actual.Title)
+	assert.Empty(t, actual.Items)
+
+	stop := errors.New("stop")
+	titles = []string{}
+	_, err = fp.ParseStream(strings.NewReader(feed), func(item *rss.Item) error {
+		titles = append(titles, item.Title)
+		return stop
+	})
+
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, []string{"One"}, titles)
+}
+
+func TestParser_DisableExtensions(t *testing.T) {
+	feed := `<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+<channel><itunes:owner><itunes:name>Owner</itunes:name></itunes:owner><title>Channel</title>
+<item><itunes:author>Author</itunes:author><title>Item</title></item></channel></rss>`
+
+	fp := &rss.Parser{DisableExtensions: true}
+	actual, err := fp.Parse(strings.NewReader(feed))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Channel", actual.Title)
+	assert.Nil(t, actual.Extensions)
+	assert.Nil(t, actual.ITunesExt)
+	assert.Equal(t, "Item", actual.Items[0].Title)
+	assert.Nil(t, actual.Items[0].Extensions)
+	assert.Nil(t, actual.Items[0].ITunesExt)
+}
+
+func TestParser_Charset(t *testing.T) {
+	feed := "<?xml version=\"1.0\" encoding=\"utf-8\"?><rss version=\"2.0\"><channel><title>Caf\xe9</title></channel></rss>"
+
+	fp := &rss.Parser{Charset: "windows-1252"}
+	actual, err := fp.Parse(strings.NewReader(feed))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Café", actual.Title)
+
+	fp = &rss.Parser{Charset: "x-unknown"}
+	_, err = fp.Parse(strings.NewReader(feed))
+	assert.NotNil(t, err)
+}
+
+func TestParser_NamespacePrefixes(t *testing.T) {
+	feed := `<rss version="2.0" xmlns:dublin="http://purl.org/dc/elements/1.1/" xmlns:ex="http://example.org/ex">
+<channel>
+<item><dublin:creator>One</dublin:creator><ex:tag>a</ex:tag><a:tag xmlns:a="http://example.org/other">a</a:tag></item>
+<item xmlns:d="http://purl.org/dc/elements/1.1/" xmlns:x="http://example.org/ex"><d:creator>Two</d:creator><x:tag>b</x:tag><b:tag xmlns:b="http://example.org/other">b</b:tag></item>
+</channel></rss>`
+
+	fp := &rss.Parser{}
+	actual, err := fp.Parse(strings.NewReader(feed))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "One", actual.Items[0].DublinCoreExt.Creator[0])
+	assert.Equal(t, "Two", actual.Items[1].DublinCoreExt.Creator[0])
+	for _, item := range actual.Items {
+		assert.Len(t, item.Extensions, 3)
+		assert.Contains(t, item.Extensions, "dc")
+		assert.Contains(t, item.Extensions, "ex")
+		assert.Contains(t, item.Extensions, "a")
+	}
+}
+
+func BenchmarkParser_DisableExtensions(b *testing.B) {
+	feed := &bytes.Buffer{}
+	feed.WriteString(`<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd" xmlns:media="http://search.yahoo.com/mrss/">
+<channel><title>Benchmark</title><itunes:author>Author</itunes:author><itunes:category text="News"/>`)
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(feed, `<item><title>Item %d</title><itunes:duration>10:00</itunes:duration><itunes:episode>%d</itunes:episode>`+
+			`<media:group><media:content url="http://example.org/%d.mp4"/><media:thumbnail url="http://example.org/%d.jpg"/></media:group></item>`,
+			i, i, i, i)
+	}
+	feed.WriteString(`</channel></rss>`)
+	data := feed.Bytes()
+
+	for _, disabled := range []bool{false, true} {
+		b.Run(fmt.Sprintf("DisableExtensions=%t", disabled), func(b *testing.B) {
+			fp := &rss.Parser{DisableExtensions: disabled}
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := fp.Parse(bytes.NewReader(data)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
 // TODO: Examples
@@ -0,0 +1,191 @@
+package gofeed
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/mmcdole/gofeed/atom"
+	"github.com/mmcdole/gofeed/internal/shared"
+	"github.com/mmcdole/gofeed/rss"
+)
+
+// Bytes of the feed that are buffered to detect its type.
+const scannerPeekSize = 64 << 10
+
+var errScannerClosed = errors.New("gofeed: scanner closed")
+
+// FeedScanner reads the items of a feed one at a time as they
+// are parsed, so arbitrarily large RSS and Atom feeds can be
+// processed without holding all of their items in memory. JSON
+// feeds aren't streamed: they are read and parsed in full
+// first, up to MaxBytes, and then handed out the same way.
+//
+// Items are translated on their own, so item fields that the
+// translator derives from the feed itself are left empty. Feeds
+// that aren't well-formed XML are parsed leniently unless
+// Strict is set, but can't be marked as Bozo as their items
+// have already been handed out. Use Parse when either matters.
+//
+// Call Close once done with a scanner that hasn't returned an
+// error from NextItem yet, to stop the parsing goroutine.
+type FeedScanner struct {
+	items chan *Item
+	stop  chan struct{}
+	done  chan struct{}
+	once  sync.Once
+
+	// Set by the parsing goroutine before done is closed
+	feed *Feed
+	err  error
+}
+
+// NewScanner starts scanning feed with the options of f. The
+// scanner reads from feed in the background until it reaches
+// the end of the feed or is closed.
+func (f *Parser) NewScanner(feed io.Reader) *FeedScanner {
+	s := &FeedScanner{
+		items: make(chan *Item),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go func() {
+		defer close(s.done)
+		s.feed, s.err = f.scan(bufio.NewReaderSize(feed, scannerPeekSize), s.emit)
+	}()
+	return s
+}
+
+// NextItem returns the next item of the feed. It returns
+// io.EOF after the last item, or the error that stopped
+// parsing.
+func (s *FeedScanner) NextItem() (*Item, error) {
+	select {
+	case item := <-s.items:
+		return item, nil
+	case <-s.done:
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+}
+
+// Metadata returns the feed level fields of the feed, with
+// Items left empty. They are only known once NextItem has
+// returned io.EOF, since they may follow the items in the
+// document; until then Metadata returns nil.
+func (s *FeedScanner) Metadata() *Feed {
+	select {
+	case <-s.done:
+		return s.feed
+	default:
+		return nil
+	}
+}
+
+// Close stops parsing and waits for the scanner to let go of
+// the reader it was created with.
+func (s *FeedScanner) Close() error {
+	s.once.Do(func() { close(s.stop) })
+	<-s.done
+	return nil
+}
+
+// emit hands item to NextItem, returning errScannerClosed
+// once the scanner has been closed.
+func (s *FeedScanner) emit(item *Item) error {
+	select {
+	case s.items <- item:
+		return nil
+	case <-s.stop:
+		return errScannerClosed
+	}
+}
+
+// scan parses feed, handing each of its items to emit, and
+// returns the feed level fields.
+func (f *Parser) scan(feed *bufio.Reader, emit func(*Item) error) (*Feed, error) {
+	head, _ := feed.Peek(scannerPeekSize)
+
+	// Each item is translated as the only item of a feed, so
+	// the paths of its warnings are fixed up with its index.
+	var warnings []ParseWarning
+	n := 0
+	emitTranslated := func(t Translator, single interface{}) error {
+		result, err := translate(t, single)
+		if err != nil {
+			return err
+		}
+		f.postProcess(result)
+		for _, w := range result.Warnings {
+			if strings.HasPrefix(w.Path, "items[0]") {
+				w.Path = fmt.Sprintf("items[%d]", n) + strings.TrimPrefix(w.Path, "items[0]")
+			}
+			warnings = append(warnings, w)
+		}
+		for _, item := range result.Items {
+			item.Index = n
+			if err := emit(item); err != nil {
+				return err
+			}
+			n++
+		}
+		return nil
+	}
+
+	var result *Feed
+	var err error
+	switch detectFeedType(head) {
+	case FeedTypeAtom:
+		ap := f.atomParser(f.Strict)
+		ap.Charset = f.Charset
+		var af *atom.Feed
+		af, err = ap.ParseStream(feed, func(entry *atom.Entry) error {
+			return emitTranslated(f.atomTrans(), &atom.Feed{Entries: []*atom.Entry{entry}})
+		})
+		if err == nil {
+			result, err = translate(f.atomTrans(), af)
+		}
+	case FeedTypeRSS:
+		rp := f.rssParser(f.Strict)
+		rp.Charset = f.Charset
+		var rf *rss.Feed
+		rf, err = rp.ParseStream(feed, func(item *rss.Item) error {
+			return emitTranslated(f.rssTrans(), &rss.Feed{Items: []*rss.Item{item}})
+		})
+		if err == nil {
+			result, err = translate(f.rssTrans(), rf)
+		}
+	case FeedTypeJSON:
+		// JSON isn't streamed, so bound what is buffered
+		pr := shared.NewPositionReader(feed, shared.NewLimits(f.MaxItems, f.MaxElementDepth, f.MaxBytes).MaxBytes)
+		defer pr.Release()
+		buf, readErr := io.ReadAll(pr)
+		if readErr != nil {
+			return nil, readErr
+		}
+		if result, err = f.ParseBytes(buf); err != nil {
+			return nil, err
+		}
+		for _, item := range result.Items {
+			if err := emit(item); err != nil {
+				return nil, err
+			}
+		}
+		result.Items = []*Item{}
+		return result, nil
+	default:
+		return nil, ErrFeedTypeNotDetected
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.Warnings = append(result.Warnings, warnings...)
+	f.postProcess(result)
+	return result, nil
+}
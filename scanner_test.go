@@ -0,0 +1,80 @@
+package gofeed_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedScanner(t *testing.T) {
+	var scannerTests = []struct {
+		name string
+		feed string
+	}{
+		{"rss", `<rss version="2.0"><channel><item><title>One</title></item>
+<item><title>Two</title></item><title>Feed</title><item><title>Three</title></item></channel></rss>`},
+		{"atom", `<feed xmlns="http://www.w3.org/2005/Atom"><entry><title>One</title></entry>
+<entry><title>Two</title></entry><title>Feed</title><entry><title>Three</title></entry></feed>`},
+		{"json", `{"version": "https://jsonfeed.org/version/1.1", "title": "Feed",
+"items": [{"id": "1", "title": "One"}, {"id": "2", "title": "Two"}, {"id": "3", "title": "Three"}]}`},
+	}
+
+	for _, test := range scannerTests {
+		s := gofeed.NewParser().NewScanner(strings.NewReader(test.feed))
+		assert.Nil(t, s.Metadata(), test.name)
+
+		titles := []string{}
+		indexes := []int{}
+		for {
+			item, err := s.NextItem()
+			if err == io.EOF {
+				break
+			}
+			if !assert.NoError(t, err, test.name) {
+				break
+			}
+			titles = append(titles, item.Title)
+			indexes = append(indexes, item.Index)
+		}
+		assert.Equal(t, []string{"One", "Two", "Three"}, titles, test.name)
+		assert.Equal(t, []int{0, 1, 2}, indexes, test.name)
+		if assert.NotNil(t, s.Metadata(), test.name) {
+			assert.Equal(t, "Feed", s.Metadata().Title, test.name)
+			assert.Empty(t, s.Metadata().Items, test.name)
+		}
+		assert.NoError(t, s.Close(), test.name)
+	}
+}
+
+func TestFeedScanner_Close(t *testing.T) {
+	feed := `<rss version="2.0"><channel>` + strings.Repeat(`<item><title>Item</title></item>`, 10) + `</channel></rss>`
+	s := gofeed.NewParser().NewScanner(strings.NewReader(feed))
+
+	item, err := s.NextItem()
+	assert.NoError(t, err)
+	assert.Equal(t, "Item", item.Title)
+
+	assert.NoError(t, s.Close())
+	_, err = s.NextItem()
+	assert.Error(t, err)
+	assert.NotEqual(t, io.EOF, err)
+}
+
+func TestFeedScanner_Error(t *testing.T) {
+	s := gofeed.NewParser().NewScanner(strings.NewReader(`<html></html>`))
+	_, err := s.NextItem()
+	assert.ErrorIs(t, err, gofeed.ErrFeedTypeNotDetected)
+	assert.Nil(t, s.Metadata())
+}
+
+func TestFeedScanner_JSONLimit(t *testing.T) {
+	fp := gofeed.NewParser()
+	fp.MaxBytes = 64
+	feed := `{"version": "https://jsonfeed.org/version/1.1", "title": "Feed", "items": [{"id": "1"}]}`
+	s := fp.NewScanner(strings.NewReader(feed))
+	_, err := s.NextItem()
+	assert.ErrorIs(t, err, gofeed.ErrLimitExceeded)
+}
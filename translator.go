@@ -1,18 +1,18 @@
 package gofeed
 
 import (
-	"bytes"
 	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/PuerkitoBio/goquery"
 	"github.com/mmcdole/gofeed/atom"
 	ext "github.com/mmcdole/gofeed/extensions"
 	"github.com/mmcdole/gofeed/internal/shared"
 	"github.com/mmcdole/gofeed/json"
 	"github.com/mmcdole/gofeed/rss"
-	"golang.org/x/net/html"
 )
 
 // Translator converts a particular feed (atom.Feed or rss.Feed of json.Feed)
@@ -21,13 +21,107 @@ type Translator interface {
 	Translate(feed interface{}) (*Feed, error)
 }
 
+// TranslationError is returned by the default translators,
+// together with the translated feed, when values of the source
+// feed couldn't be carried over, such as dates that couldn't
+// be parsed. The feed is complete otherwise, so callers that
+// want best-effort results can keep it and treat the error as
+// a list of warnings. Issues the source parser already warned
+// about are not repeated here.
+type TranslationError struct {
+	Warnings []ParseWarning
+}
+
+func (e *TranslationError) Error() string {
+	msgs := make([]string, len(e.Warnings))
+	for i, w := range e.Warnings {
+		msgs[i] = w.String()
+	}
+	return fmt.Sprintf("translated feed is missing %d value(s): %s", len(e.Warnings), strings.Join(msgs, "; "))
+}
+
+// translationError checks the dates of a translated feed and
+// returns a *TranslationError for those that couldn't be
+// parsed, or nil if there are none.
+func translationError(result *Feed) error {
+	// Feed dates are often taken from an item, so a message
+	// warned about for any item also covers the feed itself.
+	warned := map[string]bool{}
+	markWarned := func(w ParseWarning) {
+		warned[warningKey(w)] = true
+		warned[w.Message] = true
+	}
+	for _, w := range result.Warnings {
+		markWarned(w)
+	}
+
+	var warnings []ParseWarning
+	checkDate := func(path, text string, parsed *time.Time) {
+		w := ParseWarning{Path: path, Message: fmt.Sprintf("unparseable date %q", text)}
+		if text != "" && parsed == nil && !warned[warningKey(w)] {
+			warnings = append(warnings, w)
+			markWarned(w)
+		}
+	}
+
+	// Items first, so the feed dates can be checked against them
+	for i, item := range result.Items {
+		if item == nil {
+			continue
+		}
+		checkDate(fmt.Sprintf("items[%d] > updated", i), item.Updated, item.UpdatedParsed)
+		checkDate(fmt.Sprintf("items[%d] > published", i), item.Published, item.PublishedParsed)
+	}
+	checkDate("updated", result.Updated, result.UpdatedParsed)
+	checkDate("published", result.Published, result.PublishedParsed)
+
+	if len(warnings) == 0 {
+		return nil
+	}
+	return &TranslationError{Warnings: warnings}
+}
+
+// Matches the item a warning's path points into, in the
+// paths of the source parsers as well as the translator's.
+var warningItemRgx = regexp.MustCompile(`\b(?:item|entry|items)\[(\d+)\]`)
+
+// warningKey identifies a warning by its message and the item
+// it is about, or the feed itself, so a translated value the
+// source parser already warned about is recognized even
+// though the paths are spelled differently.
+func warningKey(w ParseWarning) string {
+	if m := warningItemRgx.FindStringSubmatch(w.Path); m != nil {
+		return "items[" + m[1] + "] " + w.Message
+	}
+	return w.Message
+}
+
 // DefaultRSSTranslator converts an rss.Feed struct
 // into the generic Feed struct.
 //
 // This default implementation defines a set of
 // mapping rules between rss.Feed -> Feed
 // for each of the fields in Feed.
-type DefaultRSSTranslator struct{}
+type DefaultRSSTranslator struct {
+	// PreferDublinCoreCreator makes dc:creator take
+	// precedence over <author> for Item.Author, since
+	// <author> is meant to be an email address while
+	// dc:creator usually holds a display name.
+	PreferDublinCoreCreator bool
+
+	// GUIDAsLink makes Item.Link fall back to the item's
+	// guid when the item has no <link> and the guid is a
+	// permalink, i.e. isPermaLink isn't "false" and the
+	// guid is an http(s) url.
+	GUIDAsLink bool
+
+	// SplitDublinCoreCreators splits dc:creator values that
+	// list several people, e.g. "Alice, Bob and Carol", into
+	// one Item.Authors entry each. The combined value is left
+	// in DublinCoreExt. It is off by default since some names
+	// legitimately contain commas.
+	SplitDublinCoreCreators bool
+}
 
 // Translate converts an RSS feed into the universal
 // feed type.
@@ -42,44 +136,75 @@ func (t *DefaultRSSTranslator) Translate(feed interface{}) (*Feed, error) {
 	result.Description = t.translateFeedDescription(rss)
 	result.Link = t.translateFeedLink(rss)
 	result.Links = t.translateFeedLinks(rss)
+	result.RelLinks = t.translateRelLinks(rss.Extensions)
 	result.FeedLink = t.translateFeedFeedLink(rss)
+	result.Hubs = t.translateFeedHubs(rss)
+	result.Complete, result.Archive = feedHistory(rss.Extensions)
 	result.Updated = t.translateFeedUpdated(rss)
 	result.UpdatedParsed = t.translateFeedUpdatedParsed(rss)
 	result.Published = t.translateFeedPublished(rss)
 	result.PublishedParsed = t.translateFeedPublishedParsed(rss)
 	result.Author = t.translateFeedAuthor(rss)
 	result.Authors = t.translateFeedAuthors(rss)
+	result.ManagingEditor = t.translateFeedManagingEditor(rss)
+	result.WebMaster = t.translateFeedWebMaster(rss)
 	result.Language = t.translateFeedLanguage(rss)
 	result.Image = t.translateFeedImage(rss)
+	result.Icon = t.translateFeedIcon(rss)
 	result.Copyright = t.translateFeedCopyright(rss)
 	result.Generator = t.translateFeedGenerator(rss)
+	result.GeneratorInfo = t.translateFeedGeneratorInfo(rss)
 	result.Categories = t.translateFeedCategories(rss)
+	result.ContentRating = t.translateFeedContentRating(rss)
+	result.TTL = t.translateFeedTTL(rss)
 	result.Items = t.translateFeedItems(rss)
 	result.ITunesExt = rss.ITunesExt
 	result.DublinCoreExt = rss.DublinCoreExt
+	result.DublinCoreTermsExt = rss.DublinCoreTermsExt
 	result.Extensions = rss.Extensions
+	result.Namespaces = rss.Namespaces
+	result.ItemCount = rss.ItemCount
 	result.FeedVersion = rss.Version
 	result.FeedType = "rss"
-	return result, nil
+	result.Warnings = t.translateFeedWarnings(rss)
+	return result, translationError(result)
 }
 
 func (t *DefaultRSSTranslator) translateFeedItem(rssItem *rss.Item) (item *Item) {
 	item = &Item{}
 	item.Title = t.translateItemTitle(rssItem)
 	item.Description = t.translateItemDescription(rssItem)
+	item.DescriptionType = t.translateItemDescriptionType(item.Description)
 	item.Content = t.translateItemContent(rssItem)
+	item.ContentType = t.translateItemContentType(rssItem)
 	item.Link = t.translateItemLink(rssItem)
 	item.Links = t.translateItemLinks(rssItem)
+	item.RelLinks = t.translateRelLinks(rssItem.Extensions)
+	item.Updated = t.translateItemUpdated(rssItem)
+	item.UpdatedParsed = t.translateItemUpdatedParsed(rssItem)
 	item.Published = t.translateItemPublished(rssItem)
 	item.PublishedParsed = t.translateItemPublishedParsed(rssItem)
 	item.Author = t.translateItemAuthor(rssItem)
 	item.Authors = t.translateItemAuthors(rssItem)
 	item.GUID = t.translateItemGUID(rssItem)
+	item.Language = t.translateItemLanguage(rssItem)
 	item.Image = t.translateItemImage(rssItem)
+	item.Copyright = t.translateItemCopyright(rssItem)
 	item.Categories = t.translateItemCategories(rssItem)
+	item.ContentRating = t.translateItemContentRating(rssItem)
 	item.Enclosures = t.translateItemEnclosures(rssItem)
 	item.DublinCoreExt = rssItem.DublinCoreExt
+	item.DublinCoreTermsExt = rssItem.DublinCoreTermsExt
 	item.ITunesExt = rssItem.ITunesExt
+	item.MediaExt = rssItem.MediaExt
+	item.ThreadingExt = threadingExtension(rssItem.Extensions)
+	item.InReplyTo = inReplyToRefs(item.ThreadingExt)
+	item.CommentsURL = t.translateItemCommentsURL(rssItem)
+	item.CommentsLink = t.translateItemCommentsLink(rssItem)
+	item.CommentCount = t.translateItemCommentCount(rssItem)
+	item.SourceFeed = t.translateItemSourceFeed(rssItem)
+	item.GeoExt = geoExtension(rssItem.Extensions)
+	item.EventExt = eventExtension(rssItem.Extensions)
 	item.Extensions = rssItem.Extensions
 	item.Custom = rssItem.Custom
 	return
@@ -126,6 +251,47 @@ func (t *DefaultRSSTranslator) translateFeedFeedLink(rss *rss.Feed) (link string
 	return
 }
 
+// translateRelLinks returns the embedded atom:link elements
+// of a feed or item with their relation and media type, e.g.
+// to find a JSON Feed advertised as an alternate format.
+func (t *DefaultRSSTranslator) translateRelLinks(extensions ext.Extensions) (links []*Link) {
+	atomExtensions := t.extensionsForKeys([]string{"atom", "atom10", "atom03"}, extensions)
+	for _, ex := range atomExtensions {
+		for _, l := range ex["link"] {
+			if l.Attrs["href"] == "" {
+				continue
+			}
+			rel := l.Attrs["rel"]
+			if rel == "" {
+				rel = "alternate"
+			}
+			links = append(links, &Link{
+				Href:     l.Attrs["href"],
+				Rel:      rel,
+				Type:     l.Attrs["type"],
+				Hreflang: l.Attrs["hreflang"],
+				Title:    l.Attrs["title"],
+				Length:   l.Attrs["length"],
+			})
+		}
+	}
+	return
+}
+
+// translateFeedHubs returns the WebSub hubs advertised
+// by embedded atom:link elements with rel="hub".
+func (t *DefaultRSSTranslator) translateFeedHubs(rss *rss.Feed) (hubs []string) {
+	atomExtensions := t.extensionsForKeys([]string{"atom", "atom10", "atom03"}, rss.Extensions)
+	for _, ex := range atomExtensions {
+		for _, l := range ex["link"] {
+			if l.Attrs["rel"] == "hub" && l.Attrs["href"] != "" {
+				hubs = append(hubs, l.Attrs["href"])
+			}
+		}
+	}
+	return
+}
+
 func (t *DefaultRSSTranslator) translateFeedLinks(rss *rss.Feed) (links []string) {
 	if len(rss.Links) > 0 {
 		links = append(links, rss.Links...)
@@ -148,6 +314,8 @@ func (t *DefaultRSSTranslator) translateFeedUpdated(rss *rss.Feed) (updated stri
 		updated = rss.LastBuildDate
 	} else if rss.DublinCoreExt != nil && rss.DublinCoreExt.Date != nil {
 		updated = t.firstEntry(rss.DublinCoreExt.Date)
+	} else {
+		updated = t.extensionUpdated(rss.Extensions)
 	}
 	return
 }
@@ -155,8 +323,7 @@ func (t *DefaultRSSTranslator) translateFeedUpdated(rss *rss.Feed) (updated stri
 func (t *DefaultRSSTranslator) translateFeedUpdatedParsed(rss *rss.Feed) (updated *time.Time) {
 	if rss.LastBuildDateParsed != nil {
 		updated = rss.LastBuildDateParsed
-	} else if rss.DublinCoreExt != nil && rss.DublinCoreExt.Date != nil {
-		dateText := t.firstEntry(rss.DublinCoreExt.Date)
+	} else if dateText := t.translateFeedUpdated(rss); dateText != "" {
 		date, err := shared.ParseDate(dateText)
 		if err == nil {
 			updated = &date
@@ -209,14 +376,27 @@ func (t *DefaultRSSTranslator) translateFeedAuthors(rss *rss.Feed) (authors []*P
 	if author := t.translateFeedAuthor(rss); author != nil {
 		authors = []*Person{author}
 	}
+	if rss.DublinCoreExt != nil {
+		authors = t.appendAuthors(authors, rss.DublinCoreExt.Creator)
+	}
 	return
 }
 
+func (t *DefaultRSSTranslator) translateFeedManagingEditor(rss *rss.Feed) (editor *Person) {
+	return t.translatePerson(rss.ManagingEditor)
+}
+
+func (t *DefaultRSSTranslator) translateFeedWebMaster(rss *rss.Feed) (webMaster *Person) {
+	return t.translatePerson(rss.WebMaster)
+}
+
 func (t *DefaultRSSTranslator) translateFeedLanguage(rss *rss.Feed) (language string) {
 	if rss.Language != "" {
 		language = rss.Language
 	} else if rss.DublinCoreExt != nil && rss.DublinCoreExt.Language != nil {
 		language = t.firstEntry(rss.DublinCoreExt.Language)
+	} else {
+		language = rss.XMLLang
 	}
 	return
 }
@@ -243,11 +423,20 @@ func (t *DefaultRSSTranslator) translateFeedImage(rss *rss.Feed) *Image {
 	return firstImageFromHtmlDocument(rss.Description)
 }
 
+func (t *DefaultRSSTranslator) translateFeedIcon(rss *rss.Feed) (icon string) {
+	if rss.ITunesExt != nil {
+		icon = rss.ITunesExt.Image
+	}
+	return
+}
+
 func (t *DefaultRSSTranslator) translateFeedCopyright(rss *rss.Feed) (rights string) {
 	if rss.Copyright != "" {
 		rights = rss.Copyright
 	} else if rss.DublinCoreExt != nil && rss.DublinCoreExt.Rights != nil {
 		rights = t.firstEntry(rss.DublinCoreExt.Rights)
+	} else {
+		rights = creativeCommonsLicense(rss.Extensions)
 	}
 	return
 }
@@ -256,6 +445,21 @@ func (t *DefaultRSSTranslator) translateFeedGenerator(rss *rss.Feed) (generator
 	return rss.Generator
 }
 
+func (t *DefaultRSSTranslator) translateFeedGeneratorInfo(rss *rss.Feed) (generator *Generator) {
+	if rss.Generator != "" {
+		generator = &Generator{Value: rss.Generator}
+	}
+	return
+}
+
+func (t *DefaultRSSTranslator) translateFeedContentRating(rss *rss.Feed) *ContentRating {
+	var explicit string
+	if rss.ITunesExt != nil {
+		explicit = rss.ITunesExt.Explicit
+	}
+	return newContentRating(rss.Rating, explicit, feedMediaRatings(rss.Extensions))
+}
+
 func (t *DefaultRSSTranslator) translateFeedCategories(rss *rss.Feed) (categories []string) {
 	cats := []string{}
 	if rss.Categories != nil {
@@ -264,9 +468,8 @@ func (t *DefaultRSSTranslator) translateFeedCategories(rss *rss.Feed) (categorie
 		}
 	}
 
-	if rss.ITunesExt != nil && rss.ITunesExt.Keywords != "" {
-		keywords := strings.Split(rss.ITunesExt.Keywords, ",")
-		cats = append(cats, keywords...)
+	if rss.ITunesExt != nil {
+		cats = append(cats, rss.ITunesExt.KeywordList...)
 	}
 
 	if rss.ITunesExt != nil && rss.ITunesExt.Categories != nil {
@@ -289,10 +492,23 @@ func (t *DefaultRSSTranslator) translateFeedCategories(rss *rss.Feed) (categorie
 	return
 }
 
+func (t *DefaultRSSTranslator) translateFeedTTL(rss *rss.Feed) (ttl time.Duration) {
+	return rss.TTLParsed
+}
+
+func (t *DefaultRSSTranslator) translateFeedWarnings(rss *rss.Feed) (warnings []ParseWarning) {
+	for _, w := range rss.Warnings {
+		warnings = append(warnings, ParseWarning{Path: w.Path, Message: w.Message})
+	}
+	return
+}
+
 func (t *DefaultRSSTranslator) translateFeedItems(rss *rss.Feed) (items []*Item) {
 	items = []*Item{}
 	for _, i := range rss.Items {
-		items = append(items, t.translateFeedItem(i))
+		item := t.translateFeedItem(i)
+		item.Index = len(items)
+		items = append(items, item)
 	}
 	return
 }
@@ -317,14 +533,55 @@ func (t *DefaultRSSTranslator) translateItemDescription(rssItem *rss.Item) (desc
 	return
 }
 
+// RSS descriptions and their fallbacks are all treated as HTML.
+func (t *DefaultRSSTranslator) translateItemDescriptionType(desc string) (descType string) {
+	if desc != "" {
+		descType = "html"
+	}
+	return
+}
+
 func (t *DefaultRSSTranslator) translateItemContent(rssItem *rss.Item) (content string) {
 	return rssItem.Content
 }
 
+func (t *DefaultRSSTranslator) translateItemContentType(rssItem *rss.Item) (contentType string) {
+	if rssItem.Content != "" {
+		contentType = "html"
+	}
+	return
+}
+
+func (t *DefaultRSSTranslator) translateItemSourceFeed(rssItem *rss.Item) (source *SourceFeed) {
+	if rssItem.Source != nil && (rssItem.Source.Title != "" || rssItem.Source.URL != "") {
+		source = &SourceFeed{
+			Title: rssItem.Source.Title,
+			URL:   rssItem.Source.URL,
+		}
+	}
+	return
+}
+
 func (t *DefaultRSSTranslator) translateItemLink(rssItem *rss.Item) (link string) {
+	if rssItem.Link == "" && t.GUIDAsLink && isPermalinkGUID(rssItem.GUID) {
+		return strings.TrimSpace(rssItem.GUID.Value)
+	}
 	return rssItem.Link
 }
 
+// isPermalinkGUID reports whether guid can be used as the
+// link of its item. isPermaLink defaults to true.
+func isPermalinkGUID(guid *rss.GUID) bool {
+	if guid == nil || strings.EqualFold(strings.TrimSpace(guid.IsPermalink), "false") {
+		return false
+	}
+	u, err := url.Parse(strings.TrimSpace(guid.Value))
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
 func (t *DefaultRSSTranslator) translateItemLinks(rssItem *rss.Item) (links []string) {
 	if len(rssItem.Links) > 0 {
 		links = append(links, rssItem.Links...)
@@ -333,15 +590,11 @@ func (t *DefaultRSSTranslator) translateItemLinks(rssItem *rss.Item) (links []st
 }
 
 func (t *DefaultRSSTranslator) translateItemUpdated(rssItem *rss.Item) (updated string) {
-	if rssItem.DublinCoreExt != nil && rssItem.DublinCoreExt.Date != nil {
-		updated = t.firstEntry(rssItem.DublinCoreExt.Date)
-	}
-	return updated
+	return t.extensionUpdated(rssItem.Extensions)
 }
 
 func (t *DefaultRSSTranslator) translateItemUpdatedParsed(rssItem *rss.Item) (updated *time.Time) {
-	if rssItem.DublinCoreExt != nil && rssItem.DublinCoreExt.Date != nil {
-		updatedText := t.firstEntry(rssItem.DublinCoreExt.Date)
+	if updatedText := t.translateItemUpdated(rssItem); updatedText != "" {
 		updatedDate, err := shared.ParseDate(updatedText)
 		if err == nil {
 			updated = &updatedDate
@@ -373,6 +626,13 @@ func (t *DefaultRSSTranslator) translateItemPublishedParsed(rssItem *rss.Item) (
 }
 
 func (t *DefaultRSSTranslator) translateItemAuthor(rssItem *rss.Item) (author *Person) {
+	creators := t.itemCreators(rssItem)
+	if t.PreferDublinCoreCreator {
+		if authors := t.appendAuthors(nil, creators); len(authors) > 0 {
+			return authors[0]
+		}
+	}
+
 	if rssItem.Author != "" {
 		name, address := shared.ParseNameAddress(rssItem.Author)
 		author = &Person{}
@@ -384,8 +644,8 @@ func (t *DefaultRSSTranslator) translateItemAuthor(rssItem *rss.Item) (author *P
 		author = &Person{}
 		author.Name = name
 		author.Email = address
-	} else if rssItem.DublinCoreExt != nil && rssItem.DublinCoreExt.Creator != nil {
-		dcCreator := t.firstEntry(rssItem.DublinCoreExt.Creator)
+	} else if creators != nil {
+		dcCreator := t.firstEntry(creators)
 		name, address := shared.ParseNameAddress(dcCreator)
 		author = &Person{}
 		author.Name = name
@@ -400,8 +660,39 @@ func (t *DefaultRSSTranslator) translateItemAuthor(rssItem *rss.Item) (author *P
 }
 
 func (t *DefaultRSSTranslator) translateItemAuthors(rssItem *rss.Item) (authors []*Person) {
+	creators := t.itemCreators(rssItem)
+	if t.PreferDublinCoreCreator {
+		authors = t.appendAuthors(authors, creators)
+	}
 	if author := t.translateItemAuthor(rssItem); author != nil {
-		authors = []*Person{author}
+		authors = t.appendPerson(authors, author)
+	}
+	if rssItem.Author != "" {
+		authors = t.appendAuthors(authors, []string{rssItem.Author})
+	}
+	return t.appendAuthors(authors, creators)
+}
+
+// creatorSeparatorRgx matches the separators of a dc:creator
+// listing several people, see SplitDublinCoreCreators.
+var creatorSeparatorRgx = regexp.MustCompile(`\s*[,;&]\s*|\s+and\s+`)
+
+// itemCreators returns the item's dc:creator values, split
+// into one entry per person when SplitDublinCoreCreators is set.
+func (t *DefaultRSSTranslator) itemCreators(rssItem *rss.Item) (creators []string) {
+	if rssItem.DublinCoreExt == nil || rssItem.DublinCoreExt.Creator == nil {
+		return nil
+	}
+	if !t.SplitDublinCoreCreators {
+		return rssItem.DublinCoreExt.Creator
+	}
+	creators = []string{}
+	for _, creator := range rssItem.DublinCoreExt.Creator {
+		for _, name := range creatorSeparatorRgx.Split(creator, -1) {
+			if name = strings.TrimSpace(name); name != "" {
+				creators = append(creators, name)
+			}
+		}
 	}
 	return
 }
@@ -413,6 +704,24 @@ func (t *DefaultRSSTranslator) translateItemGUID(rssItem *rss.Item) (guid string
 	return
 }
 
+func (t *DefaultRSSTranslator) translateItemCommentsURL(rssItem *rss.Item) (comments string) {
+	if wfw, ok := rssItem.Extensions["wfw"]; ok && len(wfw["commentRss"]) > 0 {
+		comments = strings.TrimSpace(wfw["commentRss"][0].Value)
+	}
+	return
+}
+
+func (t *DefaultRSSTranslator) translateItemCommentsLink(rssItem *rss.Item) (comments string) {
+	return strings.TrimSpace(rssItem.Comments)
+}
+
+func (t *DefaultRSSTranslator) translateItemCommentCount(rssItem *rss.Item) (count int) {
+	if slash, ok := rssItem.Extensions["slash"]; ok && len(slash["comments"]) > 0 {
+		count = parseCommentCount(slash["comments"][0].Value)
+	}
+	return
+}
+
 func (t *DefaultRSSTranslator) translateItemImage(rssItem *rss.Item) *Image {
 	if rssItem.ITunesExt != nil && rssItem.ITunesExt.Image != "" {
 		return &Image{URL: rssItem.ITunesExt.Image}
@@ -440,22 +749,135 @@ func (t *DefaultRSSTranslator) translateItemImage(rssItem *rss.Item) *Image {
 	return nil
 }
 
-func firstImageFromHtmlDocument(document string) *Image {
-	if doc, err := html.Parse(bytes.NewBufferString(document)); err == nil {
-		doc := goquery.NewDocumentFromNode(doc)
-		for _, node := range doc.FindMatcher(goquery.Single("img[src]")).Nodes {
-			for _, attr := range node.Attr {
-				if attr.Key == "src" {
-					return &Image{
-						URL: attr.Val,
-					}
-				}
-			}
+func (t *DefaultRSSTranslator) translateItemCopyright(rssItem *rss.Item) (rights string) {
+	if rssItem.DublinCoreExt != nil && rssItem.DublinCoreExt.Rights != nil {
+		rights = t.firstEntry(rssItem.DublinCoreExt.Rights)
+	} else {
+		rights = creativeCommonsLicense(rssItem.Extensions)
+	}
+	return
+}
+
+// creativeCommonsLicense returns the license URL of the
+// first creativeCommons:license element, if any.
+func creativeCommonsLicense(extensions ext.Extensions) (license string) {
+	if cc, ok := extensions["creativeCommons"]; ok {
+		if licenses, ok := cc["license"]; ok && len(licenses) > 0 {
+			license = licenses[0].Value
+		}
+	}
+	return
+}
+
+// dublinCoreRights returns the value of the
+// first dc:rights element, if any.
+func dublinCoreRights(extensions ext.Extensions) (rights string) {
+	if dc, ok := extensions["dc"]; ok {
+		if r, ok := dc["rights"]; ok && len(r) > 0 {
+			rights = r[0].Value
+		}
+	}
+	return
+}
+
+// threadingExtension returns the thr extensions of
+// an item, if it has any.
+func threadingExtension(extensions ext.Extensions) (thr *ext.ThreadingExtension) {
+	if t, ok := extensions["thr"]; ok {
+		thr = ext.NewThreadingExtension(t)
+	}
+	return
+}
+
+// feedHistory reports whether a feed carries the RFC 5005
+// fh:complete and fh:archive markers. They are empty elements,
+// so only their presence counts.
+func feedHistory(extensions ext.Extensions) (complete, archive bool) {
+	if fh, ok := extensions["fh"]; ok {
+		complete = len(fh["complete"]) > 0
+		archive = len(fh["archive"]) > 0
+	}
+	return
+}
+
+// geoExtension returns the GeoRSS extensions
+// of a feed or item, if it has any.
+func geoExtension(extensions ext.Extensions) (geo *ext.GeoExtension) {
+	if g, ok := extensions["georss"]; ok {
+		geo = ext.NewGeoExtension(g)
+	}
+	return
+}
+
+// eventExtension returns the xCal event extensions
+// of an item, if it has any.
+func eventExtension(extensions ext.Extensions) (event *ext.EventExtension) {
+	if x, ok := extensions["xcal"]; ok {
+		event = ext.NewEventExtension(x)
+	}
+	return
+}
+
+// isHTMLType reports whether the media type of a link is
+// that of an HTML page.
+func isHTMLType(mediaType string) bool {
+	mediaType, _, _ = strings.Cut(strings.ToLower(mediaType), ";")
+	mediaType = strings.TrimSpace(mediaType)
+	return mediaType == "text/html" || mediaType == "application/xhtml+xml"
+}
+
+// parseCommentCount parses a comment count, ignoring
+// counts that aren't non-negative integers.
+func parseCommentCount(count string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// inReplyToRefs returns the ids of the resources
+// an item is a reply to.
+func inReplyToRefs(thr *ext.ThreadingExtension) (refs []string) {
+	if thr == nil {
+		return
+	}
+	for _, r := range thr.InReplyTo {
+		if r.Ref != "" {
+			refs = append(refs, r.Ref)
 		}
 	}
+	return
+}
+
+// firstImageFromHtmlDocument returns the first image of an
+// HTML document, see firstImageSrc.
+func firstImageFromHtmlDocument(document string) *Image {
+	if src := firstImageSrc(document); src != "" {
+		return &Image{URL: src}
+	}
 	return nil
 }
 
+func (t *DefaultRSSTranslator) translateItemContentRating(rssItem *rss.Item) *ContentRating {
+	var explicit string
+	if rssItem.ITunesExt != nil {
+		explicit = rssItem.ITunesExt.Explicit
+	}
+	var media []*ext.MediaRating
+	if rssItem.MediaExt != nil {
+		media = rssItem.MediaExt.Ratings
+	}
+	return newContentRating("", explicit, media)
+}
+
+func (t *DefaultRSSTranslator) translateItemLanguage(rssItem *rss.Item) (language string) {
+	if rssItem.DublinCoreExt != nil && rssItem.DublinCoreExt.Language != nil {
+		language = t.firstEntry(rssItem.DublinCoreExt.Language)
+	}
+	return
+}
+
 func (t *DefaultRSSTranslator) translateItemCategories(rssItem *rss.Item) (categories []string) {
 	cats := []string{}
 	if rssItem.Categories != nil {
@@ -464,9 +886,8 @@ func (t *DefaultRSSTranslator) translateItemCategories(rssItem *rss.Item) (categ
 		}
 	}
 
-	if rssItem.ITunesExt != nil && rssItem.ITunesExt.Keywords != "" {
-		keywords := strings.Split(rssItem.ITunesExt.Keywords, ",")
-		cats = append(cats, keywords...)
+	if rssItem.ITunesExt != nil {
+		cats = append(cats, rssItem.ITunesExt.KeywordList...)
 	}
 
 	if rssItem.DublinCoreExt != nil && rssItem.DublinCoreExt.Subject != nil {
@@ -488,6 +909,7 @@ func (t *DefaultRSSTranslator) translateItemEnclosures(rssItem *rss.Item) (enclo
 			e.URL = enc.URL
 			e.Type = enc.Type
 			e.Length = enc.Length
+			e.LengthInt = enc.LengthInt
 			enclosures = append(enclosures, e)
 		}
 	}
@@ -499,6 +921,23 @@ func (t *DefaultRSSTranslator) translateItemEnclosures(rssItem *rss.Item) (enclo
 	return
 }
 
+// extensionUpdated returns the modification date carried by an
+// embedded atom:updated or dcterms:modified element, if any.
+func (t *DefaultRSSTranslator) extensionUpdated(extensions ext.Extensions) (updated string) {
+	atomExtensions := t.extensionsForKeys([]string{"atom", "atom10", "atom03"}, extensions)
+	for _, ex := range atomExtensions {
+		if dates, ok := ex["updated"]; ok && len(dates) > 0 && dates[0].Value != "" {
+			return dates[0].Value
+		}
+	}
+	if dcterms, ok := extensions["dcterms"]; ok {
+		if dates, ok := dcterms["modified"]; ok && len(dates) > 0 {
+			return dates[0].Value
+		}
+	}
+	return
+}
+
 func (t *DefaultRSSTranslator) extensionsForKeys(keys []string, extensions ext.Extensions) (matches []map[string][]ext.Extension) {
 	matches = []map[string][]ext.Extension{}
 
@@ -514,6 +953,48 @@ func (t *DefaultRSSTranslator) extensionsForKeys(keys []string, extensions ext.E
 	return
 }
 
+// translatePerson parses an RSS person such as
+// "email@example.org (Name)" or a bare email address.
+func (t *DefaultRSSTranslator) translatePerson(value string) (person *Person) {
+	name, address := shared.ParseNameAddress(value)
+	if name == "" && address == "" {
+		return
+	}
+	person = &Person{}
+	person.Name = name
+	person.Email = address
+	return
+}
+
+// appendAuthors adds every name/address entry (e.g. repeated
+// dc:creator elements) that isn't already one of the authors.
+func (t *DefaultRSSTranslator) appendAuthors(authors []*Person, entries []string) []*Person {
+	for _, entry := range entries {
+		name, address := shared.ParseNameAddress(entry)
+		if name == "" && address == "" {
+			continue
+		}
+		authors = t.appendPerson(authors, &Person{Name: name, Email: address})
+	}
+	return authors
+}
+
+// appendPerson appends person to authors unless it is already
+// listed. The same name with an email address on only one
+// side, as <author> and dc:creator often have, counts as the
+// same person.
+func (t *DefaultRSSTranslator) appendPerson(authors []*Person, person *Person) []*Person {
+	for _, a := range authors {
+		if a.Name == person.Name && a.Email == person.Email {
+			return authors
+		}
+		if a.Name != "" && a.Name == person.Name && (a.Email == "" || person.Email == "") {
+			return authors
+		}
+	}
+	return append(authors, person)
+}
+
 func (t *DefaultRSSTranslator) firstEntry(entries []string) (value string) {
 	if entries == nil {
 		return
@@ -547,40 +1028,72 @@ func (t *DefaultAtomTranslator) Translate(feed interface{}) (*Feed, error) {
 	result.Description = t.translateFeedDescription(atom)
 	result.Link = t.translateFeedLink(atom)
 	result.FeedLink = t.translateFeedFeedLink(atom)
+	result.Hubs = t.translateFeedHubs(atom)
+	result.Complete, result.Archive = feedHistory(atom.Extensions)
 	result.Links = t.translateFeedLinks(atom)
+	result.RelLinks = t.translateLinks(atom.Links)
+	result.ID = t.translateFeedID(atom)
 	result.Updated = t.translateFeedUpdated(atom)
 	result.UpdatedParsed = t.translateFeedUpdatedParsed(atom)
+	result.Published = t.translateFeedPublished(atom)
+	result.PublishedParsed = t.translateFeedPublishedParsed(atom)
 	result.Author = t.translateFeedAuthor(atom)
 	result.Authors = t.translateFeedAuthors(atom)
+	result.Contributors = t.translateFeedContributors(atom)
 	result.Language = t.translateFeedLanguage(atom)
 	result.Image = t.translateFeedImage(atom)
+	result.Icon = t.translateFeedIcon(atom)
 	result.Copyright = t.translateFeedCopyright(atom)
 	result.Categories = t.translateFeedCategories(atom)
+	result.ContentRating = t.translateFeedContentRating(atom)
 	result.Generator = t.translateFeedGenerator(atom)
+	result.GeneratorInfo = t.translateFeedGeneratorInfo(atom)
 	result.Items = t.translateFeedItems(atom)
+	result.ITunesExt = t.translateFeedITunesExtension(atom)
+	result.DublinCoreExt = t.translateFeedDublinCoreExtension(atom)
+	result.DublinCoreTermsExt = t.translateFeedDublinCoreTermsExtension(atom)
 	result.Extensions = atom.Extensions
+	result.Namespaces = atom.Namespaces
+	result.ItemCount = atom.EntryCount
 	result.FeedVersion = atom.Version
 	result.FeedType = "atom"
-	return result, nil
+	result.Warnings = t.translateFeedWarnings(atom)
+	return result, translationError(result)
 }
 
 func (t *DefaultAtomTranslator) translateFeedItem(entry *atom.Entry) (item *Item) {
 	item = &Item{}
 	item.Title = t.translateItemTitle(entry)
 	item.Description = t.translateItemDescription(entry)
+	item.DescriptionType = t.translateItemDescriptionType(entry)
 	item.Content = t.translateItemContent(entry)
+	item.ContentType = t.translateItemContentType(entry)
 	item.Link = t.translateItemLink(entry)
 	item.Links = t.translateItemLinks(entry)
+	item.RelLinks = t.translateLinks(entry.Links)
 	item.Updated = t.translateItemUpdated(entry)
 	item.UpdatedParsed = t.translateItemUpdatedParsed(entry)
 	item.Published = t.translateItemPublished(entry)
 	item.PublishedParsed = t.translateItemPublishedParsed(entry)
 	item.Author = t.translateItemAuthor(entry)
 	item.Authors = t.translateItemAuthors(entry)
+	item.Contributors = t.translateItemContributors(entry)
 	item.GUID = t.translateItemGUID(entry)
+	item.Language = t.translateItemLanguage(entry)
 	item.Image = t.translateItemImage(entry)
+	item.Copyright = t.translateItemCopyright(entry)
 	item.Categories = t.translateItemCategories(entry)
 	item.Enclosures = t.translateItemEnclosures(entry)
+	item.MediaExt = t.translateItemMediaExtension(entry)
+	item.ContentRating = t.translateItemContentRating(entry, item.MediaExt)
+	item.ThreadingExt = threadingExtension(entry.Extensions)
+	item.InReplyTo = inReplyToRefs(item.ThreadingExt)
+	item.CommentsURL = t.translateItemCommentsURL(entry)
+	item.CommentsLink = t.translateItemCommentsLink(entry)
+	item.CommentCount = t.translateItemCommentCount(entry)
+	item.SourceFeed = t.translateItemSourceFeed(entry)
+	item.GeoExt = geoExtension(entry.Extensions)
+	item.EventExt = eventExtension(entry.Extensions)
 	item.Extensions = entry.Extensions
 	return
 }
@@ -609,6 +1122,15 @@ func (t *DefaultAtomTranslator) translateFeedFeedLink(atom *atom.Feed) (link str
 	return
 }
 
+func (t *DefaultAtomTranslator) translateFeedHubs(atom *atom.Feed) (hubs []string) {
+	for _, l := range atom.Links {
+		if l.Rel == "hub" && l.Href != "" {
+			hubs = append(hubs, l.Href)
+		}
+	}
+	return
+}
+
 func (t *DefaultAtomTranslator) translateFeedLinks(atom *atom.Feed) (links []string) {
 	for _, l := range atom.Links {
 		if l.Rel == "" || l.Rel == "alternate" || l.Rel == "self" {
@@ -618,6 +1140,52 @@ func (t *DefaultAtomTranslator) translateFeedLinks(atom *atom.Feed) (links []str
 	return
 }
 
+// Replies links to HTML pages are the comments page rather
+// than the comment feed. Links without a type are taken to
+// be the feed.
+func (t *DefaultAtomTranslator) translateItemCommentsURL(entry *atom.Entry) (comments string) {
+	for _, l := range entry.Links {
+		if l.Rel == "replies" && !isHTMLType(l.Type) {
+			return l.Href
+		}
+	}
+	return
+}
+
+func (t *DefaultAtomTranslator) translateItemCommentsLink(entry *atom.Entry) (comments string) {
+	for _, l := range entry.Links {
+		if l.Rel == "replies" && isHTMLType(l.Type) {
+			return l.Href
+		}
+	}
+	return
+}
+
+func (t *DefaultAtomTranslator) translateItemCommentCount(entry *atom.Entry) (count int) {
+	if l := t.firstLinkWithType("replies", entry.Links); l != nil {
+		count = parseCommentCount(l.Count)
+	}
+	return
+}
+
+func (t *DefaultAtomTranslator) translateLinks(atomLinks []*atom.Link) (links []*Link) {
+	for _, l := range atomLinks {
+		links = append(links, &Link{
+			Href:     l.Href,
+			Rel:      l.Rel,
+			Type:     l.Type,
+			Hreflang: l.Hreflang,
+			Title:    l.Title,
+			Length:   l.Length,
+		})
+	}
+	return
+}
+
+func (t *DefaultAtomTranslator) translateFeedID(atom *atom.Feed) (id string) {
+	return atom.ID
+}
+
 func (t *DefaultAtomTranslator) translateFeedUpdated(atom *atom.Feed) (updated string) {
 	return atom.Updated
 }
@@ -626,30 +1194,28 @@ func (t *DefaultAtomTranslator) translateFeedUpdatedParsed(atom *atom.Feed) (upd
 	return atom.UpdatedParsed
 }
 
+func (t *DefaultAtomTranslator) translateFeedPublished(atom *atom.Feed) (published string) {
+	return atom.Published
+}
+
+func (t *DefaultAtomTranslator) translateFeedPublishedParsed(atom *atom.Feed) (published *time.Time) {
+	return atom.PublishedParsed
+}
+
 func (t *DefaultAtomTranslator) translateFeedAuthor(atom *atom.Feed) (author *Person) {
 	a := t.firstPerson(atom.Authors)
 	if a != nil {
-		feedAuthor := Person{}
-		feedAuthor.Name = a.Name
-		feedAuthor.Email = a.Email
-		author = &feedAuthor
+		author = t.translatePerson(a)
 	}
 	return
 }
 
 func (t *DefaultAtomTranslator) translateFeedAuthors(atom *atom.Feed) (authors []*Person) {
-	if atom.Authors != nil {
-		authors = []*Person{}
-
-		for _, a := range atom.Authors {
-			authors = append(authors, &Person{
-				Name:  a.Name,
-				Email: a.Email,
-			})
-		}
-	}
+	return t.translatePersons(atom.Authors)
+}
 
-	return
+func (t *DefaultAtomTranslator) translateFeedContributors(atom *atom.Feed) (contributors []*Person) {
+	return t.translatePersons(atom.Contributors)
 }
 
 func (t *DefaultAtomTranslator) translateFeedLanguage(atom *atom.Feed) (language string) {
@@ -661,16 +1227,21 @@ func (t *DefaultAtomTranslator) translateFeedImage(atom *atom.Feed) (image *Imag
 		feedImage := Image{}
 		feedImage.URL = atom.Logo
 		image = &feedImage
-	} else if atom.Icon != "" {
-		feedImage := Image{}
-		feedImage.URL = atom.Icon
-		image = &feedImage
 	}
 	return
 }
 
+func (t *DefaultAtomTranslator) translateFeedIcon(atom *atom.Feed) (icon string) {
+	return atom.Icon
+}
+
 func (t *DefaultAtomTranslator) translateFeedCopyright(atom *atom.Feed) (rights string) {
-	return atom.Rights
+	if atom.Rights != "" {
+		rights = atom.Rights
+	} else {
+		rights = dublinCoreRights(atom.Extensions)
+	}
+	return
 }
 
 func (t *DefaultAtomTranslator) translateFeedGenerator(atom *atom.Feed) (generator string) {
@@ -689,6 +1260,17 @@ func (t *DefaultAtomTranslator) translateFeedGenerator(atom *atom.Feed) (generat
 	return
 }
 
+func (t *DefaultAtomTranslator) translateFeedGeneratorInfo(atom *atom.Feed) (generator *Generator) {
+	if atom.Generator != nil {
+		generator = &Generator{
+			Value:   atom.Generator.Value,
+			URI:     atom.Generator.URI,
+			Version: atom.Generator.Version,
+		}
+	}
+	return
+}
+
 func (t *DefaultAtomTranslator) translateFeedCategories(atom *atom.Feed) (categories []string) {
 	if atom.Categories != nil {
 		categories = []string{}
@@ -703,10 +1285,19 @@ func (t *DefaultAtomTranslator) translateFeedCategories(atom *atom.Feed) (catego
 	return
 }
 
+func (t *DefaultAtomTranslator) translateFeedWarnings(atom *atom.Feed) (warnings []ParseWarning) {
+	for _, w := range atom.Warnings {
+		warnings = append(warnings, ParseWarning{Path: w.Path, Message: w.Message})
+	}
+	return
+}
+
 func (t *DefaultAtomTranslator) translateFeedItems(atom *atom.Feed) (items []*Item) {
 	items = []*Item{}
 	for _, entry := range atom.Entries {
-		items = append(items, t.translateFeedItem(entry))
+		item := t.translateFeedItem(entry)
+		item.Index = len(items)
+		items = append(items, item)
 	}
 	return
 }
@@ -719,6 +1310,16 @@ func (t *DefaultAtomTranslator) translateItemDescription(entry *atom.Entry) (des
 	return entry.Summary
 }
 
+func (t *DefaultAtomTranslator) translateItemDescriptionType(entry *atom.Entry) (descType string) {
+	if entry.Summary != "" {
+		descType = strings.ToLower(strings.TrimSpace(entry.SummaryType))
+		if descType == "" {
+			descType = "text"
+		}
+	}
+	return
+}
+
 func (t *DefaultAtomTranslator) translateItemContent(entry *atom.Entry) (content string) {
 	if entry.Content != nil {
 		content = entry.Content.Value
@@ -726,6 +1327,39 @@ func (t *DefaultAtomTranslator) translateItemContent(entry *atom.Entry) (content
 	return
 }
 
+func (t *DefaultAtomTranslator) translateItemContentType(entry *atom.Entry) (contentType string) {
+	if entry.Content != nil && entry.Content.Value != "" {
+		contentType = strings.ToLower(strings.TrimSpace(entry.Content.Type))
+		if contentType == "" {
+			contentType = "text"
+		}
+	}
+	return
+}
+
+// The self link of an Atom source is the feed itself, the
+// alternate link only the site it belongs to.
+func (t *DefaultAtomTranslator) translateItemSourceFeed(entry *atom.Entry) (source *SourceFeed) {
+	if entry.Source == nil {
+		return
+	}
+	l := t.firstLinkWithType("self", entry.Source.Links)
+	if l == nil {
+		l = t.firstLinkWithType("alternate", entry.Source.Links)
+	}
+	var href string
+	if l != nil {
+		href = l.Href
+	}
+	if entry.Source.Title != "" || href != "" {
+		source = &SourceFeed{
+			Title: entry.Source.Title,
+			URL:   href,
+		}
+	}
+	return
+}
+
 func (t *DefaultAtomTranslator) translateItemLink(entry *atom.Entry) (link string) {
 	l := t.firstLinkWithType("alternate", entry.Links)
 	if l != nil {
@@ -770,34 +1404,82 @@ func (t *DefaultAtomTranslator) translateItemPublishedParsed(entry *atom.Entry)
 func (t *DefaultAtomTranslator) translateItemAuthor(entry *atom.Entry) (author *Person) {
 	a := t.firstPerson(entry.Authors)
 	if a != nil {
-		author = &Person{}
-		author.Name = a.Name
-		author.Email = a.Email
+		author = t.translatePerson(a)
 	}
 	return
 }
 
 func (t *DefaultAtomTranslator) translateItemAuthors(entry *atom.Entry) (authors []*Person) {
-	if entry.Authors != nil {
-		authors = []*Person{}
-		for _, a := range entry.Authors {
-			authors = append(authors, &Person{
-				Name:  a.Name,
-				Email: a.Email,
-			})
-		}
-	}
-	return
+	return t.translatePersons(entry.Authors)
+}
+
+func (t *DefaultAtomTranslator) translateItemContributors(entry *atom.Entry) (contributors []*Person) {
+	return t.translatePersons(entry.Contributors)
 }
 
 func (t *DefaultAtomTranslator) translateItemGUID(entry *atom.Entry) (guid string) {
 	return entry.ID
 }
 
+func (t *DefaultAtomTranslator) translateFeedContentRating(atom *atom.Feed) *ContentRating {
+	return newContentRating("", itunesExplicit(atom.Extensions), feedMediaRatings(atom.Extensions))
+}
+
+func (t *DefaultAtomTranslator) translateItemContentRating(entry *atom.Entry, media *ext.MediaExtension) *ContentRating {
+	var ratings []*ext.MediaRating
+	if media != nil {
+		ratings = media.Ratings
+	}
+	return newContentRating("", itunesExplicit(entry.Extensions), ratings)
+}
+
+func (t *DefaultAtomTranslator) translateItemLanguage(entry *atom.Entry) (language string) {
+	return entry.Language
+}
+
 func (t *DefaultAtomTranslator) translateItemImage(entry *atom.Entry) (image *Image) {
 	return nil
 }
 
+func (t *DefaultAtomTranslator) translateItemCopyright(entry *atom.Entry) (rights string) {
+	if entry.Rights != "" {
+		rights = entry.Rights
+	} else {
+		rights = dublinCoreRights(entry.Extensions)
+	}
+	return
+}
+
+// translateFeedITunesExtension builds the typed itunes
+// extension of feeds such as Atom wrapped podcasts.
+func (t *DefaultAtomTranslator) translateFeedITunesExtension(atom *atom.Feed) (itunes *ext.ITunesFeedExtension) {
+	if i, ok := atom.Extensions["itunes"]; ok {
+		itunes = ext.NewITunesFeedExtension(i)
+	}
+	return
+}
+
+func (t *DefaultAtomTranslator) translateFeedDublinCoreExtension(atom *atom.Feed) (dc *ext.DublinCoreExtension) {
+	if d, ok := atom.Extensions["dc"]; ok {
+		dc = ext.NewDublinCoreExtension(d)
+	}
+	return
+}
+
+func (t *DefaultAtomTranslator) translateFeedDublinCoreTermsExtension(atom *atom.Feed) (dcterms *ext.DublinCoreTermsExtension) {
+	if d, ok := atom.Extensions["dcterms"]; ok {
+		dcterms = ext.NewDublinCoreTermsExtension(d)
+	}
+	return
+}
+
+func (t *DefaultAtomTranslator) translateItemMediaExtension(entry *atom.Entry) (media *ext.MediaExtension) {
+	if m, ok := entry.Extensions["media"]; ok {
+		media = ext.NewMediaExtension(m)
+	}
+	return
+}
+
 func (t *DefaultAtomTranslator) translateItemCategories(entry *atom.Entry) (categories []string) {
 	if entry.Categories != nil {
 		categories = []string{}
@@ -820,6 +1502,7 @@ func (t *DefaultAtomTranslator) translateItemEnclosures(entry *atom.Entry) (encl
 				enclosure := &Enclosure{}
 				enclosure.URL = e.Href
 				enclosure.Length = e.Length
+				enclosure.LengthInt = shared.ParseByteCount(e.Length)
 				enclosure.Type = e.Type
 				enclosures = append(enclosures, enclosure)
 			}
@@ -845,6 +1528,24 @@ func (t *DefaultAtomTranslator) firstLinkWithType(linkType string, links []*atom
 	return nil
 }
 
+func (t *DefaultAtomTranslator) translatePerson(person *atom.Person) *Person {
+	return &Person{
+		Name:  person.Name,
+		Email: person.Email,
+		URI:   person.URI,
+	}
+}
+
+func (t *DefaultAtomTranslator) translatePersons(persons []*atom.Person) (result []*Person) {
+	if persons != nil {
+		result = []*Person{}
+		for _, p := range persons {
+			result = append(result, t.translatePerson(p))
+		}
+	}
+	return
+}
+
 func (t *DefaultAtomTranslator) firstPerson(persons []*atom.Person) (person *atom.Person) {
 	if persons == nil || len(persons) == 0 {
 		return
@@ -876,8 +1577,10 @@ func (t *DefaultJSONTranslator) Translate(feed interface{}) (*Feed, error) {
 	result.Link = t.translateFeedLink(json)
 	result.FeedLink = t.translateFeedFeedLink(json)
 	result.Links = t.translateFeedLinks(json)
+	result.RelLinks = t.translateFeedRelLinks(json)
 	result.Description = t.translateFeedDescription(json)
 	result.Image = t.translateFeedImage(json)
+	result.Icon = t.translateFeedIcon(json)
 	result.Author = t.translateFeedAuthor(json)
 	result.Authors = t.translateFeedAuthors(json)
 	result.Language = t.translateFeedLanguage(json)
@@ -888,23 +1591,25 @@ func (t *DefaultJSONTranslator) Translate(feed interface{}) (*Feed, error) {
 	result.PublishedParsed = t.translateFeedPublishedParsed(json)
 	result.FeedType = "json"
 	// TODO UserComment is missing in global Feed
-	// TODO NextURL is missing in global Feed
-	// TODO Favicon is missing in global Feed
 	// TODO Exipred is missing in global Feed
 	// TODO Hubs is not supported in json.Feed
-	// TODO Extensions is not supported in json.Feed
-	return result, nil
+	result.JSONExtensions = json.Extensions
+	return result, translationError(result)
 }
 
 func (t *DefaultJSONTranslator) translateFeedItem(jsonItem *json.Item) (item *Item) {
 	item = &Item{}
 	item.GUID = t.translateItemGUID(jsonItem)
+	item.Language = t.translateItemLanguage(jsonItem)
 	item.Link = t.translateItemLink(jsonItem)
 	item.Links = t.translateItemLinks(jsonItem)
 	item.Title = t.translateItemTitle(jsonItem)
 	item.Content = t.translateItemContent(jsonItem)
+	item.ContentType = t.translateItemContentType(jsonItem)
 	item.Description = t.translateItemDescription(jsonItem)
+	item.DescriptionType = t.translateItemDescriptionType(item.Description)
 	item.Image = t.translateItemImage(jsonItem)
+	item.BannerImage = t.translateItemBannerImage(jsonItem)
 	item.Published = t.translateItemPublished(jsonItem)
 	item.PublishedParsed = t.translateItemPublishedParsed(jsonItem)
 	item.Updated = t.translateItemUpdated(jsonItem)
@@ -913,8 +1618,8 @@ func (t *DefaultJSONTranslator) translateFeedItem(jsonItem *json.Item) (item *It
 	item.Authors = t.translateItemAuthors(jsonItem)
 	item.Categories = t.translateItemCategories(jsonItem)
 	item.Enclosures = t.translateItemEnclosures(jsonItem)
+	item.JSONExtensions = jsonItem.Extensions
 	// TODO ExternalURL is missing in global Feed
-	// TODO BannerImage is missing in global Feed
 	return
 }
 
@@ -953,6 +1658,23 @@ func (t *DefaultJSONTranslator) translateFeedLinks(json *json.Feed) (links []str
 	return
 }
 
+// translateFeedRelLinks maps the feed's urls to their Atom
+// link relations, home_page_url to "alternate", feed_url to
+// "self" and next_url to "next", so JSON feeds can be paged
+// like Atom ones.
+func (t *DefaultJSONTranslator) translateFeedRelLinks(json *json.Feed) (links []*Link) {
+	if json.HomePageURL != "" {
+		links = append(links, &Link{Href: json.HomePageURL, Rel: "alternate"})
+	}
+	if json.FeedURL != "" {
+		links = append(links, &Link{Href: json.FeedURL, Rel: "self"})
+	}
+	if json.NextURL != "" {
+		links = append(links, &Link{Href: json.NextURL, Rel: "next"})
+	}
+	return
+}
+
 func (t *DefaultJSONTranslator) translateFeedUpdated(json *json.Feed) (updated string) {
 	if len(json.Items) > 0 {
 		updated = json.Items[0].DateModified
@@ -1023,8 +1745,12 @@ func (t *DefaultJSONTranslator) translateFeedLanguage(json *json.Feed) (language
 	return
 }
 
+// translateFeedImage maps the JSON Feed icon to Feed.Image and
+// translateFeedIcon the favicon to Feed.Icon. This swaps the
+// names, but JSON Feed's icon is the large image an Atom logo
+// is while its favicon is the small one an Atom icon is, so
+// both formats fill Image and Icon with the same kind of asset.
 func (t *DefaultJSONTranslator) translateFeedImage(json *json.Feed) (image *Image) {
-	// Using the Icon rather than the image
 	// icon (optional, string) is the URL of an image for the feed suitable to be used in a timeline. It should be square and relatively large — such as 512 x 512
 	if json.Icon != "" {
 		image = &Image{}
@@ -1033,10 +1759,21 @@ func (t *DefaultJSONTranslator) translateFeedImage(json *json.Feed) (image *Imag
 	return
 }
 
+func (t *DefaultJSONTranslator) translateFeedIcon(json *json.Feed) (icon string) {
+	// favicon (optional, string) is the URL of an image for the feed suitable to be used in a source list
+	return json.Favicon
+}
+
 func (t *DefaultJSONTranslator) translateFeedItems(json *json.Feed) (items []*Item) {
 	items = []*Item{}
 	for _, i := range json.Items {
-		items = append(items, t.translateFeedItem(i))
+		item := t.translateFeedItem(i)
+		// Index is unset for items that weren't parsed
+		item.Index = len(items)
+		if i.Index > item.Index {
+			item.Index = i.Index
+		}
+		items = append(items, item)
 	}
 	return
 }
@@ -1055,6 +1792,14 @@ func (t *DefaultJSONTranslator) translateItemDescription(jsonItem *json.Item) (d
 	return
 }
 
+// JSON Feed summaries are always plain text.
+func (t *DefaultJSONTranslator) translateItemDescriptionType(desc string) (descType string) {
+	if desc != "" {
+		descType = "text"
+	}
+	return
+}
+
 func (t *DefaultJSONTranslator) translateItemContent(jsonItem *json.Item) (content string) {
 	if jsonItem.ContentHTML != "" {
 		content = jsonItem.ContentHTML
@@ -1064,6 +1809,15 @@ func (t *DefaultJSONTranslator) translateItemContent(jsonItem *json.Item) (conte
 	return
 }
 
+func (t *DefaultJSONTranslator) translateItemContentType(jsonItem *json.Item) (contentType string) {
+	if jsonItem.ContentHTML != "" {
+		contentType = "html"
+	} else if jsonItem.ContentText != "" {
+		contentType = "text"
+	}
+	return
+}
+
 func (t *DefaultJSONTranslator) translateItemLink(jsonItem *json.Item) (link string) {
 	return jsonItem.URL
 }
@@ -1150,6 +1904,10 @@ func (t *DefaultJSONTranslator) translateItemGUID(jsonItem *json.Item) (guid str
 	return
 }
 
+func (t *DefaultJSONTranslator) translateItemLanguage(jsonItem *json.Item) (language string) {
+	return jsonItem.Language
+}
+
 func (t *DefaultJSONTranslator) translateItemImage(jsonItem *json.Item) (image *Image) {
 	if jsonItem.Image != "" {
 		image = &Image{}
@@ -1161,6 +1919,13 @@ func (t *DefaultJSONTranslator) translateItemImage(jsonItem *json.Item) (image *
 	return
 }
 
+func (t *DefaultJSONTranslator) translateItemBannerImage(jsonItem *json.Item) (image *Image) {
+	if jsonItem.BannerImage != "" {
+		image = &Image{URL: jsonItem.BannerImage}
+	}
+	return
+}
+
 func (t *DefaultJSONTranslator) translateItemCategories(jsonItem *json.Item) (categories []string) {
 	if len(jsonItem.Tags) > 0 {
 		categories = jsonItem.Tags
@@ -1174,9 +1939,11 @@ func (t *DefaultJSONTranslator) translateItemEnclosures(jsonItem *json.Item) (en
 			e := &Enclosure{}
 			e.URL = attachment.URL
 			e.Type = attachment.MimeType
-			e.Length = fmt.Sprintf("%d", attachment.DurationInSeconds)
-			// Title is not defined in global enclosure
-			// SizeInBytes is not defined in global enclosure
+			if attachment.SizeInBytes > 0 {
+				e.Length = strconv.FormatInt(attachment.SizeInBytes, 10)
+			}
+			e.LengthInt = attachment.SizeInBytes
+			// Title and duration are not defined in global enclosure
 			enclosures = append(enclosures, e)
 		}
 	}
@@ -57,6 +57,111 @@ func TestDefaultRSSTranslator_Translate_WrongType(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestDefaultRSSTranslator_PreferDublinCoreCreator(t *testing.T) {
+	feed := `<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/"><channel>
+<item><author>editor@example.org</author><dc:creator>Jane Roe</dc:creator></item>
+<item><author>editor@example.org</author></item>
+</channel></rss>`
+
+	rssFeed, err := (&rss.Parser{}).Parse(strings.NewReader(feed))
+	assert.Nil(t, err)
+
+	translator := &gofeed.DefaultRSSTranslator{PreferDublinCoreCreator: true}
+	actual, err := translator.Translate(rssFeed)
+	assert.Nil(t, err)
+
+	assert.Equal(t, &gofeed.Person{Name: "Jane Roe"}, actual.Items[0].Author)
+	assert.Equal(t, []*gofeed.Person{{Name: "Jane Roe"}, {Email: "editor@example.org"}}, actual.Items[0].Authors)
+	// Falls back to <author> without a dc:creator
+	assert.Equal(t, &gofeed.Person{Email: "editor@example.org"}, actual.Items[1].Author)
+
+	actual, _ = (&gofeed.DefaultRSSTranslator{}).Translate(rssFeed)
+	assert.Equal(t, &gofeed.Person{Email: "editor@example.org"}, actual.Items[0].Author)
+	assert.Equal(t, []*gofeed.Person{{Email: "editor@example.org"}, {Name: "Jane Roe"}}, actual.Items[0].Authors)
+}
+
+func TestDefaultRSSTranslator_SplitDublinCoreCreators(t *testing.T) {
+	feed := `<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/"><channel>
+<item><dc:creator>Alice, Bob and Carol</dc:creator><dc:creator>Dave; Erin &amp; Alice</dc:creator></item>
+<item><dc:creator>Sandra Anderson</dc:creator></item>
+</channel></rss>`
+
+	rssFeed, err := (&rss.Parser{}).Parse(strings.NewReader(feed))
+	assert.Nil(t, err)
+
+	translator := &gofeed.DefaultRSSTranslator{SplitDublinCoreCreators: true}
+	actual, err := translator.Translate(rssFeed)
+	assert.Nil(t, err)
+
+	assert.Equal(t, &gofeed.Person{Name: "Alice"}, actual.Items[0].Author)
+	assert.Equal(t, []*gofeed.Person{{Name: "Alice"}, {Name: "Bob"}, {Name: "Carol"}, {Name: "Dave"}, {Name: "Erin"}}, actual.Items[0].Authors)
+	assert.Equal(t, []string{"Alice, Bob and Carol", "Dave; Erin & Alice"}, actual.Items[0].DublinCoreExt.Creator)
+	assert.Equal(t, []*gofeed.Person{{Name: "Sandra Anderson"}}, actual.Items[1].Authors)
+
+	actual, _ = (&gofeed.DefaultRSSTranslator{}).Translate(rssFeed)
+	assert.Equal(t, []*gofeed.Person{{Name: "Alice, Bob and Carol"}, {Name: "Dave; Erin & Alice"}}, actual.Items[0].Authors)
+}
+
+func TestDefaultRSSTranslator_GUIDAsLink(t *testing.T) {
+	feed := `<rss version="2.0"><channel>
+<item><guid>http://example.org/posts/1</guid></item>
+<item><guid isPermaLink="true">https://example.org/posts/2</guid></item>
+<item><guid isPermaLink="false">http://example.org/posts/3</guid></item>
+<item><guid>urn:uuid:1225c695-cfb8-4ebb-aaaa-80da344efa6a</guid></item>
+<item><link>http://example.org/posts/5.html</link><guid>http://example.org/posts/5</guid></item>
+</channel></rss>`
+
+	rssFeed, err := (&rss.Parser{}).Parse(strings.NewReader(feed))
+	assert.Nil(t, err)
+
+	translator := &gofeed.DefaultRSSTranslator{GUIDAsLink: true}
+	actual, err := translator.Translate(rssFeed)
+	assert.Nil(t, err)
+
+	links := []string{}
+	for _, item := range actual.Items {
+		links = append(links, item.Link)
+	}
+	assert.Equal(t, []string{
+		"http://example.org/posts/1",
+		"https://example.org/posts/2",
+		"",
+		"",
+		"http://example.org/posts/5.html",
+	}, links)
+
+	actual, _ = (&gofeed.DefaultRSSTranslator{}).Translate(rssFeed)
+	assert.Empty(t, actual.Items[0].Link)
+}
+
+func TestDefaultRSSTranslator_TranslationError(t *testing.T) {
+	feed := `<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/"><channel>
+<item><title>One</title><dc:date>last tuesday</dc:date></item>
+<item><title>Two</title><pubDate>yesterday</pubDate></item>
+<item><title>Three</title><dc:date>2024-02-25T10:00:00Z</dc:date></item>
+<item><title>Four</title><dc:date>last tuesday</dc:date></item>
+</channel></rss>`
+
+	rssFeed, err := (&rss.Parser{}).Parse(strings.NewReader(feed))
+	assert.Nil(t, err)
+
+	actual, err := (&gofeed.DefaultRSSTranslator{}).Translate(rssFeed)
+
+	// The feed is translated in full despite the error
+	assert.Len(t, actual.Items, 4)
+	assert.NotNil(t, actual.Items[2].PublishedParsed)
+
+	// The bad pubDate was already reported by the rss parser,
+	// the same bad date in two items is reported for both.
+	var terr *gofeed.TranslationError
+	if assert.ErrorAs(t, err, &terr) {
+		assert.Equal(t, []gofeed.ParseWarning{
+			{Path: "items[0] > published", Message: `unparseable date "last tuesday"`},
+			{Path: "items[3] > published", Message: `unparseable date "last tuesday"`},
+		}, terr.Warnings)
+	}
+}
+
 func TestDefaultAtomTranslator_Translate(t *testing.T) {
 	files, _ := filepath.Glob("testdata/translator/atom/*.xml")
 	for _, f := range files {